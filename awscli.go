@@ -0,0 +1,79 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// operation is a scan or query with its parameters. The TUI keeps the last
+// one so it can be reproduced as an AWS CLI command.
+type operation struct {
+	kind         string // "scan" or "query"
+	table        string
+	index        string
+	keyCondition string
+	filter       string
+	names        map[string]string
+	values       map[string]types.AttributeValue
+	// maxItems stops reading once this many items are loaded (0 = no limit)
+	maxItems int
+	// count only counts matching items (Select=COUNT)
+	count bool
+	// projection limits a scan to some attributes, e.g. only the keys
+	projection string
+
+	// Key attributes used in a query's key condition, and the partition key
+	// value queried
+	pkAttr  string
+	skAttr  string
+	pkValue types.AttributeValue
+}
+
+// cliCommand returns the equivalent `aws dynamodb` command line
+func (op *operation) cliCommand(endpoint, region string) string {
+	args := []string{"aws", "dynamodb", op.kind, "--table-name", shellQuote(op.table)}
+	if op.index != "" {
+		args = append(args, "--index-name", shellQuote(op.index))
+	}
+	if op.keyCondition != "" {
+		args = append(args, "--key-condition-expression", shellQuote(op.keyCondition))
+	}
+	if op.filter != "" {
+		args = append(args, "--filter-expression", shellQuote(op.filter))
+	}
+	if op.projection != "" {
+		args = append(args, "--projection-expression", shellQuote(op.projection))
+	}
+	if op.count {
+		args = append(args, "--select", "COUNT")
+	}
+	if len(op.names) > 0 {
+		data, _ := json.Marshal(op.names)
+		args = append(args, "--expression-attribute-names", shellQuote(string(data)))
+	}
+	if len(op.values) > 0 {
+		data, _ := json.Marshal(itemToNative(op.values))
+		args = append(args, "--expression-attribute-values", shellQuote(string(data)))
+	}
+	if endpoint != "" {
+		args = append(args, "--endpoint-url", shellQuote(endpoint))
+	}
+	if region != "" {
+		args = append(args, "--region", shellQuote(region))
+	}
+	return strings.Join(args, " ")
+}
+
+// shellQuote quotes s for a POSIX shell if it contains anything but safe
+// characters
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_.:/=") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// outputFormat is how batch mode writes items
+type outputFormat string
+
+const (
+	// outputJSONL is one simplified JSON item per line, for jq and friends
+	outputJSONL outputFormat = "jsonl"
+	// outputJSON is a single JSON array of simplified items
+	outputJSON outputFormat = "json"
+	// outputDynamoDBJSONL is one DynamoDB JSON item ({"a": {"S": "x"}}) per
+	// line, for re-importing with the AWS CLI
+	outputDynamoDBJSONL outputFormat = "dynamodb-jsonl"
+	// outputDynamoDBJSON is a single JSON array of DynamoDB JSON items
+	outputDynamoDBJSON outputFormat = "dynamodb-json"
+)
+
+// outputFormats lists the valid -output values
+var outputFormats = []outputFormat{outputJSONL, outputJSON, outputDynamoDBJSONL, outputDynamoDBJSON}
+
+// parseOutputFormat validates an -output value
+func parseOutputFormat(s string) (outputFormat, error) {
+	names := make([]string, len(outputFormats))
+	for i, f := range outputFormats {
+		if string(f) == s {
+			return f, nil
+		}
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unknown output format %q (expected %s)", s, strings.Join(names, ", "))
+}
+
+// runBatch runs a scan or query without the TUI and writes the items to w
+// in format
+func runBatch(db *DDB, op *operation, format outputFormat, w io.Writer) error {
+	items, err := db.Run(context.Background(), op, nil)
+	if err != nil {
+		return err
+	}
+	return writeItems(w, items, format)
+}
+
+// writeItems writes items to w in format
+func writeItems(w io.Writer, items []map[string]types.AttributeValue, format outputFormat) error {
+	values := make([]any, len(items))
+	for i, item := range items {
+		if format == outputDynamoDBJSONL || format == outputDynamoDBJSON {
+			values[i] = itemToNative(item)
+		} else {
+			values[i] = attributeValueToInterface(item)
+		}
+	}
+
+	out := bufio.NewWriter(w)
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	if format == outputJSON || format == outputDynamoDBJSON {
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(values); err != nil {
+			return err
+		}
+		return out.Flush()
+	}
+	for _, v := range values {
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+	return out.Flush()
+}
+
+// batchOperation builds the operation for -scan or -query from the
+// remaining command line arguments
+func batchOperation(scanTable, queryTable string, args []string) (*operation, error) {
+	if scanTable != "" && queryTable != "" {
+		return nil, fmt.Errorf("-scan and -query are mutually exclusive")
+	}
+	if scanTable != "" {
+		op := &operation{kind: "scan", table: scanTable}
+		if len(args) > 1 {
+			return nil, fmt.Errorf("usage: dui -scan table [index]")
+		}
+		if len(args) == 1 {
+			op.index = args[0]
+		}
+		return op, nil
+	}
+	op, err := parseQuery(queryTable, args)
+	if err != nil {
+		return nil, fmt.Errorf("usage: dui -query table [index] pk=value [sk<op>value]")
+	}
+	return op, nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Config is the user's settings, read from config.json in the dui config
+// directory (e.g. ~/.config/dui/config.json). Every field is optional.
+type Config struct {
+	// IgnoreCase makes attr=value filters case-insensitive by default
+	IgnoreCase bool `json:"ignore_case"`
+
+	// GroupNumbers shows Number values with thousands separators
+	GroupNumbers bool `json:"group_numbers"`
+
+	// ShowTimestamps shows epoch Number attributes as dates. TimestampAttrs
+	// are the attribute name glob patterns it applies to.
+	ShowTimestamps bool     `json:"show_timestamps"`
+	TimestampAttrs []string `json:"timestamp_attrs"`
+
+	// ColorTypes colors the list's JSON column by value type
+	ColorTypes bool `json:"color_types"`
+
+	// SortKeys splits composite sort keys (e.g. ORDER#2024#001) into
+	// separate list columns, keyed by table name
+	SortKeys map[string]SortKeyFormat `json:"sort_keys"`
+
+	// DisplayOrder lists, by table name, the attributes shown first in the
+	// list and item view, after the keys, e.g. ["name", "createdAt"]; the
+	// rest follow sorted by name. OrderEdits opens the editor in the same
+	// order.
+	DisplayOrder map[string][]string `json:"display_order"`
+	OrderEdits   bool                `json:"order_edits"`
+
+	// Density is the list layout: "compact" (keys only), "normal", or
+	// "comfortable" (wider key columns). KeyWidth sets the width of the key
+	// columns in normal density (default 20).
+	Density  string `json:"density"`
+	KeyWidth int    `json:"key_width"`
+
+	// MaxItems caps how many items a scan or query loads (default 1000,
+	// negative for no limit)
+	MaxItems int `json:"max_items"`
+
+	// TablePrefix lists only the tables whose names start with it, e.g.
+	// "dev_". StripTablePrefix leaves the prefix out of displayed names.
+	TablePrefix      string `json:"table_prefix"`
+	StripTablePrefix bool   `json:"strip_table_prefix"`
+
+	// Indent is the indentation of item JSON in the item view and the
+	// editor: "tab" or a number of spaces (default 2)
+	Indent string `json:"indent"`
+
+	// HistorySize caps how many viewed items and partitions backspace can
+	// step back through (default 50)
+	HistorySize int `json:"history_size"`
+
+	// ConfirmThreshold makes deleting more items than it ask for the count
+	// (or "yes") to be typed instead of y (default 0, off)
+	ConfirmThreshold int `json:"confirm_threshold"`
+
+	// ReadOnly refuses every write: saves, deletes, :set, /rename, /seed,
+	// and /truncate. The -read-only flag turns it on too.
+	ReadOnly bool `json:"read_only"`
+}
+
+// SortKeyFormat describes how a table's sort key values are composed
+type SortKeyFormat struct {
+	// Delimiter separates the segments, e.g. "#"
+	Delimiter string `json:"delimiter"`
+
+	// Labels name the segments. When set, they also fix the segment count:
+	// anything past the last label stays in the last column.
+	Labels []string `json:"labels"`
+}
+
+// defaultMaxItems is the item cap when max_items isn't configured
+const defaultMaxItems = 1000
+
+// tableLabel is the name a table is shown with
+func (c *Config) tableLabel(name string) string {
+	if c.StripTablePrefix && name != c.TablePrefix {
+		return strings.TrimPrefix(name, c.TablePrefix)
+	}
+	return name
+}
+
+// indent returns the configured JSON indentation, two spaces unless set to
+// "tab" or a number of spaces from 0 to 8
+func (c *Config) indent() string {
+	if strings.EqualFold(c.Indent, "tab") {
+		return "\t"
+	}
+	if n, err := strconv.Atoi(c.Indent); err == nil && n >= 0 && n <= 8 {
+		return strings.Repeat(" ", n)
+	}
+	return "  "
+}
+
+// historySize returns the effective history cap
+func (c *Config) historySize() int {
+	if c.HistorySize <= 0 {
+		return defaultHistorySize
+	}
+	return c.HistorySize
+}
+
+// maxItems returns the effective item cap, 0 meaning no limit
+func (c *Config) maxItems() int {
+	switch {
+	case c.MaxItems < 0:
+		return 0
+	case c.MaxItems == 0:
+		return defaultMaxItems
+	default:
+		return c.MaxItems
+	}
+}
+
+// loadConfig reads the config file. A missing file yields the defaults.
+func loadConfig() (*Config, error) {
+	cfg := &Config{}
+	dir, err := configDir()
+	if err != nil {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config file: %w", err)
+	}
+	return cfg, nil
+}
@@ -5,58 +5,237 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	streamstypes "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
 )
 
 type DDB struct {
 	client   *dynamodb.Client
+	streams  *dynamodbstreams.Client
 	endpoint string
+	region   string
+	insecure bool
+	useAWS   bool // credentials from the default AWS chain even if local
+	regional bool // endpoint is the region's AWS endpoint, following the region
 }
 
 type TableInfo struct {
-	Name          string
-	PartitionKey  string
-	SortKey       string
-	GlobalIndexes []IndexInfo
-	LocalIndexes  []IndexInfo
+	Name         string
+	PartitionKey string
+	SortKey      string
+	// PartitionKeyType and SortKeyType are the key attributes' types: S, N,
+	// or B
+	PartitionKeyType string
+	SortKeyType      string
+	GlobalIndexes    []IndexInfo
+	LocalIndexes     []IndexInfo
+	// DescribeErr is why the table couldn't be described; its keys are
+	// unknown until set with /keys
+	DescribeErr error
+}
+
+// keysLabel describes the table's primary key for headers and lists
+func (t *TableInfo) keysLabel() string {
+	switch {
+	case t.PartitionKey == "":
+		return " (unknown schema)"
+	case t.SortKey != "":
+		return fmt.Sprintf(" (PK: %s, SK: %s)", keyLabel(t.PartitionKey, t.PartitionKeyType), keyLabel(t.SortKey, t.SortKeyType))
+	default:
+		return fmt.Sprintf(" (PK: %s)", keyLabel(t.PartitionKey, t.PartitionKeyType))
+	}
+}
+
+// keyLabel is a key attribute with its type, e.g. "ts[N]"
+func keyLabel(name, typ string) string {
+	if typ == "" {
+		return name
+	}
+	return name + "[" + typ + "]"
 }
 
 type IndexInfo struct {
 	Name         string
 	PartitionKey string
 	SortKey      string
+	// Projection is ALL, KEYS_ONLY, or INCLUDE; NonKeyAttributes lists the
+	// attributes projected by INCLUDE
+	Projection       string
+	NonKeyAttributes []string
+}
+
+// index returns the global or local secondary index named name, or nil
+func (t *TableInfo) index(name string) *IndexInfo {
+	for _, idx := range t.indexes() {
+		if idx.Name == name {
+			return &idx
+		}
+	}
+	return nil
+}
+
+// indexes returns the global and local secondary indexes of the table
+func (t *TableInfo) indexes() []IndexInfo {
+	return append(append([]IndexInfo{}, t.GlobalIndexes...), t.LocalIndexes...)
+}
+
+// checkKeys returns an error naming the first key attribute of the table
+// that item is missing or has empty
+func (t *TableInfo) checkKeys(item map[string]types.AttributeValue) error {
+	for _, key := range []string{t.PartitionKey, t.SortKey} {
+		if key == "" {
+			continue
+		}
+		av, ok := item[key]
+		if !ok {
+			return fmt.Errorf("item is missing key attribute %q", key)
+		}
+		empty := false
+		switch v := av.(type) {
+		case *types.AttributeValueMemberS:
+			empty = v.Value == ""
+		case *types.AttributeValueMemberN:
+			empty = v.Value == ""
+		case *types.AttributeValueMemberB:
+			empty = len(v.Value) == 0
+		default:
+			return fmt.Errorf("key attribute %q must be a string, number, or binary", key)
+		}
+		if empty {
+			return fmt.Errorf("key attribute %q is empty", key)
+		}
+	}
+	return nil
 }
 
-func NewDB(endpoint string) (*DDB, error) {
+// NewDB connects to endpoint. An empty region uses the one from the AWS
+// config or environment. Local endpoints get static credentials, as
+// DynamoDB local accepts any; other endpoints, or any with useAWS, get them
+// from the environment, AWS_PROFILE, or an instance role. An empty endpoint
+// with useAWS is the region's real DynamoDB.
+func NewDB(endpoint, region string, insecure, useAWS bool) (*DDB, error) {
 	ctx := context.Background()
 
-	// Use static credentials for local DynamoDB.
-	// Doesn't work yet with real DynamoDB by design.
-	staticCreds := credentials.NewStaticCredentialsProvider("local", "local", "")
+	if endpoint != "" || !useAWS {
+		if err := validateEndpoint(endpoint); err != nil {
+			return nil, err
+		}
+	}
 
-	cfg, err := config.LoadDefaultConfig(ctx,
-		config.WithCredentialsProvider(staticCreds),
-	)
+	var opts []func(*config.LoadOptions) error
+	if !useAWS && isLocalEndpoint(endpoint) {
+		staticCreds := credentials.NewStaticCredentialsProvider("local", "local", "")
+		opts = append(opts, config.WithCredentialsProvider(staticCreds))
+	}
+	if insecure {
+		httpClient := awshttp.NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
+			if tr.TLSClientConfig == nil {
+				tr.TLSClientConfig = &tls.Config{}
+			}
+			tr.TLSClientConfig.InsecureSkipVerify = true
+		})
+		opts = append(opts, config.WithHTTPClient(httpClient))
+	}
+	if region != "" {
+		opts = append(opts, config.WithRegion(region))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
-	client := dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
-		o.BaseEndpoint = aws.String(endpoint)
-	})
+	var client *dynamodb.Client
+	var streams *dynamodbstreams.Client
+	if endpoint == "" {
+		client = dynamodb.NewFromConfig(cfg)
+		streams = dynamodbstreams.NewFromConfig(cfg)
+	} else {
+		client = dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+			o.BaseEndpoint = aws.String(endpoint)
+		})
+		// DynamoDB Local serves streams on the same endpoint
+		streams = dynamodbstreams.NewFromConfig(cfg, func(o *dynamodbstreams.Options) {
+			o.BaseEndpoint = aws.String(endpoint)
+		})
+	}
 
-	return &DDB{
+	db := &DDB{
 		client:   client,
+		streams:  streams,
 		endpoint: endpoint,
-	}, nil
+		region:   cfg.Region,
+		insecure: insecure,
+		useAWS:   useAWS,
+	}
+	if endpoint == "" {
+		db.endpoint = "https://dynamodb." + cfg.Region + ".amazonaws.com"
+		db.regional = true
+	}
+	return db, nil
+}
+
+// isLocalEndpoint reports whether endpoint is a local DynamoDB rather than
+// AWS: a loopback host, or plain http, which AWS doesn't serve
+func isLocalEndpoint(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "http" || u.Hostname() == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(u.Hostname())
+	return ip != nil && ip.IsLoopback()
+}
+
+// Ping checks that the endpoint answers, with a cheap ListTables call
+func (db *DDB) Ping(ctx context.Context) error {
+	_, err := db.client.ListTables(ctx, &dynamodb.ListTablesInput{Limit: aws.Int32(1)})
+	if err != nil {
+		return fmt.Errorf("cannot reach DynamoDB at %s: %w", db.endpoint, err)
+	}
+	return nil
+}
+
+// validateEndpoint checks that endpoint is an http or https URL with a host
+func validateEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid endpoint %q: expected http://host[:port][/path] or https://...", endpoint)
+	}
+	return nil
+}
+
+// target describes the endpoint and region the client talks to
+func (db *DDB) target() string {
+	t := db.endpoint
+	if u, err := url.Parse(db.endpoint); err == nil {
+		t = u.Host
+	}
+	if db.region != "" {
+		t += " " + db.region
+	}
+	return t
 }
 
 func (db *DDB) ListTables(ctx context.Context) ([]string, error) {
@@ -97,9 +276,22 @@ func (db *DDB) DescribeTable(ctx context.Context, tableName string) (*TableInfo,
 		}
 	}
 
+	for _, def := range out.Table.AttributeDefinitions {
+		switch aws.ToString(def.AttributeName) {
+		case info.PartitionKey:
+			info.PartitionKeyType = string(def.AttributeType)
+		case info.SortKey:
+			info.SortKeyType = string(def.AttributeType)
+		}
+	}
+
 	// Get global secondary indexes
 	for _, gsi := range out.Table.GlobalSecondaryIndexes {
 		idx := IndexInfo{Name: *gsi.IndexName}
+		if gsi.Projection != nil {
+			idx.Projection = string(gsi.Projection.ProjectionType)
+			idx.NonKeyAttributes = gsi.Projection.NonKeyAttributes
+		}
 		for _, key := range gsi.KeySchema {
 			if key.KeyType == types.KeyTypeHash {
 				idx.PartitionKey = *key.AttributeName
@@ -113,6 +305,10 @@ func (db *DDB) DescribeTable(ctx context.Context, tableName string) (*TableInfo,
 	// Get local secondary indexes
 	for _, lsi := range out.Table.LocalSecondaryIndexes {
 		idx := IndexInfo{Name: *lsi.IndexName}
+		if lsi.Projection != nil {
+			idx.Projection = string(lsi.Projection.ProjectionType)
+			idx.NonKeyAttributes = lsi.Projection.NonKeyAttributes
+		}
 		for _, key := range lsi.KeySchema {
 			if key.KeyType == types.KeyTypeHash {
 				idx.PartitionKey = *key.AttributeName
@@ -126,14 +322,206 @@ func (db *DDB) DescribeTable(ctx context.Context, tableName string) (*TableInfo,
 	return info, nil
 }
 
-func (db *DDB) Scan(ctx context.Context, tableName string, indexName string) ([]map[string]types.AttributeValue, error) {
+// DescribeTableJSON returns the full DescribeTable output of a table as
+// indented JSON
+func (db *DDB) DescribeTableJSON(ctx context.Context, tableName string) (string, error) {
+	out, err := db.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe table %s: %w", tableName, err)
+	}
+	data, err := json.MarshalIndent(out.Table, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// TimeToLiveAttribute returns the TTL attribute of a table, or "" if TTL
+// isn't enabled
+func (db *DDB) TimeToLiveAttribute(ctx context.Context, tableName string) (string, error) {
+	out, err := db.client.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe TTL of %s: %w", tableName, err)
+	}
+	desc := out.TimeToLiveDescription
+	if desc == nil || desc.TimeToLiveStatus != types.TimeToLiveStatusEnabled {
+		return "", nil
+	}
+	return aws.ToString(desc.AttributeName), nil
+}
+
+// streamRecord is a change read from a table's stream
+type streamRecord struct {
+	event    string // INSERT, MODIFY, or REMOVE
+	time     time.Time
+	keys     map[string]types.AttributeValue
+	oldImage map[string]types.AttributeValue
+	newImage map[string]types.AttributeValue
+}
+
+// OpenStream returns an iterator over the most recent shard of a table's
+// stream, starting at its oldest record for trim horizon and at new records
+// otherwise
+func (db *DDB) OpenStream(ctx context.Context, tableName string, trimHorizon bool) (string, error) {
+	out, err := db.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe table %s: %w", tableName, err)
+	}
+	spec := out.Table.StreamSpecification
+	if out.Table.LatestStreamArn == nil || spec == nil || !aws.ToBool(spec.StreamEnabled) {
+		return "", fmt.Errorf("streams aren't enabled on %s", tableName)
+	}
+	arn := out.Table.LatestStreamArn
+
+	// The most recent shard is the open one that started last
+	var latest *streamstypes.Shard
+	var startShard *string
+	for {
+		desc, err := db.streams.DescribeStream(ctx, &dynamodbstreams.DescribeStreamInput{
+			StreamArn:             arn,
+			ExclusiveStartShardId: startShard,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to describe stream of %s: %w", tableName, err)
+		}
+		for i, shard := range desc.StreamDescription.Shards {
+			if latest == nil || shardStartsAfter(shard, *latest) {
+				latest = &desc.StreamDescription.Shards[i]
+			}
+		}
+		startShard = desc.StreamDescription.LastEvaluatedShardId
+		if startShard == nil {
+			break
+		}
+	}
+	if latest == nil {
+		return "", fmt.Errorf("the stream of %s has no shards yet", tableName)
+	}
+
+	iteratorType := streamstypes.ShardIteratorTypeLatest
+	if trimHorizon {
+		iteratorType = streamstypes.ShardIteratorTypeTrimHorizon
+	}
+	it, err := db.streams.GetShardIterator(ctx, &dynamodbstreams.GetShardIteratorInput{
+		StreamArn:         arn,
+		ShardId:           latest.ShardId,
+		ShardIteratorType: iteratorType,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to open stream of %s: %w", tableName, err)
+	}
+	return aws.ToString(it.ShardIterator), nil
+}
+
+// shardStartsAfter reports whether a is a better pick than b for the most
+// recent shard: open shards win over closed ones, then the later start
+func shardStartsAfter(a, b streamstypes.Shard) bool {
+	aOpen := a.SequenceNumberRange == nil || a.SequenceNumberRange.EndingSequenceNumber == nil
+	bOpen := b.SequenceNumberRange == nil || b.SequenceNumberRange.EndingSequenceNumber == nil
+	if aOpen != bOpen {
+		return aOpen
+	}
+	var aStart, bStart string
+	if a.SequenceNumberRange != nil {
+		aStart = aws.ToString(a.SequenceNumberRange.StartingSequenceNumber)
+	}
+	if b.SequenceNumberRange != nil {
+		bStart = aws.ToString(b.SequenceNumberRange.StartingSequenceNumber)
+	}
+	// Sequence numbers are decimal strings of varying length
+	if len(aStart) != len(bStart) {
+		return len(aStart) > len(bStart)
+	}
+	return aStart > bStart
+}
+
+// StreamRecords reads the records at a shard iterator. The next iterator is
+// "" once the shard is closed and read to its end.
+func (db *DDB) StreamRecords(ctx context.Context, iterator string) ([]streamRecord, string, error) {
+	out, err := db.streams.GetRecords(ctx, &dynamodbstreams.GetRecordsInput{
+		ShardIterator: aws.String(iterator),
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read stream: %w", err)
+	}
+	records := make([]streamRecord, 0, len(out.Records))
+	for _, r := range out.Records {
+		rec := streamRecord{event: string(r.EventName)}
+		if d := r.Dynamodb; d != nil {
+			rec.time = aws.ToTime(d.ApproximateCreationDateTime)
+			rec.keys = streamItem(d.Keys)
+			rec.oldImage = streamItem(d.OldImage)
+			rec.newImage = streamItem(d.NewImage)
+		}
+		records = append(records, rec)
+	}
+	return records, aws.ToString(out.NextShardIterator), nil
+}
+
+// streamItem converts an image of a stream record, whose attribute values
+// are the streams API's own types, to an item
+func streamItem(image map[string]streamstypes.AttributeValue) map[string]types.AttributeValue {
+	if image == nil {
+		return nil
+	}
+	item := make(map[string]types.AttributeValue, len(image))
+	for k, v := range image {
+		item[k] = streamValue(v)
+	}
+	return item
+}
+
+func streamValue(av streamstypes.AttributeValue) types.AttributeValue {
+	switch v := av.(type) {
+	case *streamstypes.AttributeValueMemberS:
+		return &types.AttributeValueMemberS{Value: v.Value}
+	case *streamstypes.AttributeValueMemberN:
+		return &types.AttributeValueMemberN{Value: v.Value}
+	case *streamstypes.AttributeValueMemberB:
+		return &types.AttributeValueMemberB{Value: v.Value}
+	case *streamstypes.AttributeValueMemberBOOL:
+		return &types.AttributeValueMemberBOOL{Value: v.Value}
+	case *streamstypes.AttributeValueMemberNULL:
+		return &types.AttributeValueMemberNULL{Value: v.Value}
+	case *streamstypes.AttributeValueMemberSS:
+		return &types.AttributeValueMemberSS{Value: v.Value}
+	case *streamstypes.AttributeValueMemberNS:
+		return &types.AttributeValueMemberNS{Value: v.Value}
+	case *streamstypes.AttributeValueMemberBS:
+		return &types.AttributeValueMemberBS{Value: v.Value}
+	case *streamstypes.AttributeValueMemberM:
+		return &types.AttributeValueMemberM{Value: streamItem(v.Value)}
+	case *streamstypes.AttributeValueMemberL:
+		list := make([]types.AttributeValue, len(v.Value))
+		for i, elem := range v.Value {
+			list[i] = streamValue(elem)
+		}
+		return &types.AttributeValueMemberL{Value: list}
+	}
+	return &types.AttributeValueMemberNULL{Value: true}
+}
+
+// Scan reads the items of a table or index, stopping once maxItems have been
+// read if maxItems > 0. If onPage is not nil, it is called after each page
+// with the number of items read so far.
+func (db *DDB) Scan(ctx context.Context, tableName string, indexName string, maxItems int, onPage func(count int)) ([]map[string]types.AttributeValue, error) {
 	input := &dynamodb.ScanInput{
 		TableName: aws.String(tableName),
 	}
 	if indexName != "" {
 		input.IndexName = aws.String(indexName)
 	}
+	return db.scan(ctx, input, maxItems, onPage)
+}
 
+// scan reads the pages of a scan like Scan
+func (db *DDB) scan(ctx context.Context, input *dynamodb.ScanInput, maxItems int, onPage func(count int)) ([]map[string]types.AttributeValue, error) {
 	var items []map[string]types.AttributeValue
 	var lastKey map[string]types.AttributeValue
 
@@ -145,6 +533,14 @@ func (db *DDB) Scan(ctx context.Context, tableName string, indexName string) ([]
 		}
 
 		items = append(items, out.Items...)
+		if onPage != nil {
+			onPage(len(items))
+		}
+
+		if maxItems > 0 && len(items) >= maxItems {
+			items = items[:maxItems]
+			break
+		}
 
 		if out.LastEvaluatedKey == nil {
 			break
@@ -155,10 +551,14 @@ func (db *DDB) Scan(ctx context.Context, tableName string, indexName string) ([]
 	return items, nil
 }
 
-func (db *DDB) Query(ctx context.Context, tableName string, indexName string, keyCondition string, exprValues map[string]types.AttributeValue) ([]map[string]types.AttributeValue, error) {
+// Query reads the items matching keyCondition, stopping once maxItems have
+// been read if maxItems > 0. If onPage is not nil, it is called after each
+// page with the number of items read so far.
+func (db *DDB) Query(ctx context.Context, tableName string, indexName string, keyCondition string, exprNames map[string]string, exprValues map[string]types.AttributeValue, maxItems int, onPage func(count int)) ([]map[string]types.AttributeValue, error) {
 	input := &dynamodb.QueryInput{
 		TableName:                 aws.String(tableName),
 		KeyConditionExpression:    aws.String(keyCondition),
+		ExpressionAttributeNames:  exprNames,
 		ExpressionAttributeValues: exprValues,
 	}
 	if indexName != "" {
@@ -176,6 +576,14 @@ func (db *DDB) Query(ctx context.Context, tableName string, indexName string, ke
 		}
 
 		items = append(items, out.Items...)
+		if onPage != nil {
+			onPage(len(items))
+		}
+
+		if maxItems > 0 && len(items) >= maxItems {
+			items = items[:maxItems]
+			break
+		}
 
 		if out.LastEvaluatedKey == nil {
 			break
@@ -186,6 +594,84 @@ func (db *DDB) Query(ctx context.Context, tableName string, indexName string, ke
 	return items, nil
 }
 
+// Count returns the number of items matched by a scan or query operation
+// using Select=COUNT, so no items are transferred, and the number of items
+// read to find them, which is larger when a filter discards some. If onPage
+// is not nil, it is called after each page with the count so far.
+func (db *DDB) Count(ctx context.Context, op *operation, onPage func(count int)) (count, scanned int, err error) {
+	var filter *string
+	if op.filter != "" {
+		filter = aws.String(op.filter)
+	}
+	var index *string
+	if op.index != "" {
+		index = aws.String(op.index)
+	}
+
+	var lastKey map[string]types.AttributeValue
+	for {
+		var pageCount, pageScanned int32
+		if op.kind == "query" {
+			var out *dynamodb.QueryOutput
+			out, err = db.client.Query(ctx, &dynamodb.QueryInput{
+				TableName:                 aws.String(op.table),
+				IndexName:                 index,
+				KeyConditionExpression:    aws.String(op.keyCondition),
+				FilterExpression:          filter,
+				ExpressionAttributeNames:  op.names,
+				ExpressionAttributeValues: op.values,
+				Select:                    types.SelectCount,
+				ExclusiveStartKey:         lastKey,
+			})
+			if out != nil {
+				pageCount, pageScanned, lastKey = out.Count, out.ScannedCount, out.LastEvaluatedKey
+			}
+		} else {
+			var out *dynamodb.ScanOutput
+			out, err = db.client.Scan(ctx, &dynamodb.ScanInput{
+				TableName:                 aws.String(op.table),
+				IndexName:                 index,
+				FilterExpression:          filter,
+				ExpressionAttributeNames:  op.names,
+				ExpressionAttributeValues: op.values,
+				Select:                    types.SelectCount,
+				ExclusiveStartKey:         lastKey,
+			})
+			if out != nil {
+				pageCount, pageScanned, lastKey = out.Count, out.ScannedCount, out.LastEvaluatedKey
+			}
+		}
+		if err != nil {
+			return 0, 0, fmt.Errorf("count failed: %w", err)
+		}
+
+		count += int(pageCount)
+		scanned += int(pageScanned)
+		if onPage != nil {
+			onPage(count)
+		}
+		if lastKey == nil {
+			break
+		}
+	}
+	return count, scanned, nil
+}
+
+// Run executes a scan or query operation
+func (db *DDB) Run(ctx context.Context, op *operation, onPage func(count int)) ([]map[string]types.AttributeValue, error) {
+	if op.kind == "query" {
+		return db.Query(ctx, op.table, op.index, op.keyCondition, op.names, op.values, op.maxItems, onPage)
+	}
+	if op.projection != "" {
+		return db.scan(ctx, &dynamodb.ScanInput{
+			TableName:                aws.String(op.table),
+			ProjectionExpression:     aws.String(op.projection),
+			ExpressionAttributeNames: op.names,
+		}, op.maxItems, onPage)
+	}
+	return db.Scan(ctx, op.table, op.index, op.maxItems, onPage)
+}
+
 func (db *DDB) GetItem(ctx context.Context, tableName string, key map[string]types.AttributeValue) (map[string]types.AttributeValue, error) {
 	out, err := db.client.GetItem(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(tableName),
@@ -197,6 +683,133 @@ func (db *DDB) GetItem(ctx context.Context, tableName string, key map[string]typ
 	return out.Item, nil
 }
 
+// BatchGetItem fetches items by key, 100 keys per request, retrying any
+// unprocessed keys. Items that don't exist are simply absent from the result.
+func (db *DDB) BatchGetItem(ctx context.Context, tableName string, keys []map[string]types.AttributeValue) ([]map[string]types.AttributeValue, error) {
+	var items []map[string]types.AttributeValue
+
+	for start := 0; start < len(keys); start += 100 {
+		end := min(start+100, len(keys))
+		request := map[string]types.KeysAndAttributes{
+			tableName: {Keys: keys[start:end]},
+		}
+
+		for attempt := 0; len(request) > 0; attempt++ {
+			if attempt > 0 {
+				if err := retryBackoff(ctx, attempt); err != nil {
+					return nil, err
+				}
+			}
+			out, err := db.client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
+				RequestItems: request,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("batch get failed: %w", err)
+			}
+			items = append(items, out.Responses[tableName]...)
+			request = out.UnprocessedKeys
+		}
+	}
+
+	return items, nil
+}
+
+// retryBackoff waits before retry attempt of unprocessed batch keys or
+// writes, which DynamoDB returns when throttling: 50ms, doubling up to 2s.
+// It returns early with the context's error when cancelled.
+func retryBackoff(ctx context.Context, attempt int) error {
+	delay := min(50*time.Millisecond<<min(attempt-1, 6), 2*time.Second)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+// BatchPutItems writes items 25 per request, retrying any unprocessed
+// writes
+func (db *DDB) BatchPutItems(ctx context.Context, tableName string, items []map[string]types.AttributeValue) error {
+	writes := make([]types.WriteRequest, len(items))
+	for i, item := range items {
+		writes[i] = types.WriteRequest{PutRequest: &types.PutRequest{Item: item}}
+	}
+	if err := db.batchWrite(ctx, tableName, writes); err != nil {
+		return fmt.Errorf("batch write failed: %w", err)
+	}
+	return nil
+}
+
+// batchWrite sends writes 25 per BatchWriteItem request, retrying any
+// unprocessed writes with backoff
+func (db *DDB) batchWrite(ctx context.Context, tableName string, writes []types.WriteRequest) error {
+	for start := 0; start < len(writes); start += 25 {
+		end := min(start+25, len(writes))
+		request := map[string][]types.WriteRequest{tableName: writes[start:end]}
+
+		for attempt := 0; len(request) > 0; attempt++ {
+			if attempt > 0 {
+				if err := retryBackoff(ctx, attempt); err != nil {
+					return err
+				}
+			}
+			out, err := db.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+				RequestItems: request,
+			})
+			if err != nil {
+				return err
+			}
+			request = out.UnprocessedItems
+		}
+	}
+	return nil
+}
+
+// ScanKeys reads the primary keys of every item in a table, projecting only
+// the key attributes to keep reads small
+func (db *DDB) ScanKeys(ctx context.Context, table *TableInfo, onPage func(count int)) ([]map[string]types.AttributeValue, error) {
+	return db.Run(ctx, keysOnlyScan(table, 0), onPage)
+}
+
+// BatchDeleteItems deletes items by key 25 per request, retrying any
+// unprocessed deletes
+func (db *DDB) BatchDeleteItems(ctx context.Context, tableName string, keys []map[string]types.AttributeValue) error {
+	writes := make([]types.WriteRequest, len(keys))
+	for i, key := range keys {
+		writes[i] = types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: key}}
+	}
+	if err := db.batchWrite(ctx, tableName, writes); err != nil {
+		return fmt.Errorf("batch delete failed: %w", err)
+	}
+	return nil
+}
+
+// maxTransactItems is the most items a single transaction can read
+const maxTransactItems = 100
+
+// TransactGetItems reads items by key as one consistent snapshot. Items that
+// don't exist are left out of the result.
+func (db *DDB) TransactGetItems(ctx context.Context, tableName string, keys []map[string]types.AttributeValue) ([]map[string]types.AttributeValue, error) {
+	if len(keys) > maxTransactItems {
+		return nil, fmt.Errorf("a transaction reads at most %d items, got %d keys", maxTransactItems, len(keys))
+	}
+	gets := make([]types.TransactGetItem, len(keys))
+	for i, key := range keys {
+		gets[i] = types.TransactGetItem{Get: &types.Get{TableName: aws.String(tableName), Key: key}}
+	}
+	out, err := db.client.TransactGetItems(ctx, &dynamodb.TransactGetItemsInput{TransactItems: gets})
+	if err != nil {
+		return nil, fmt.Errorf("transactional get failed: %w", err)
+	}
+	var items []map[string]types.AttributeValue
+	for _, resp := range out.Responses {
+		if resp.Item != nil {
+			items = append(items, resp.Item)
+		}
+	}
+	return items, nil
+}
+
 func (db *DDB) PutItem(ctx context.Context, tableName string, item map[string]types.AttributeValue) error {
 	_, err := db.client.PutItem(ctx, &dynamodb.PutItemInput{
 		TableName: aws.String(tableName),
@@ -208,6 +821,67 @@ func (db *DDB) PutItem(ctx context.Context, tableName string, item map[string]ty
 	return nil
 }
 
+// maxConditionLength is the longest condition expression DynamoDB accepts
+const maxConditionLength = 4096
+
+// PutItemIf puts an item only if the stored item still has every attribute
+// of expected, so concurrent changes aren't silently overwritten. A nil
+// expected requires that no item with the key exists yet. Returns a
+// *types.ConditionalCheckFailedException when the check fails.
+func (db *DDB) PutItemIf(ctx context.Context, table *TableInfo, item, expected map[string]types.AttributeValue) error {
+	var e exprBuilder
+	condition := unchangedCondition(&e, table, expected)
+	_, err := db.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                 aws.String(table.Name),
+		Item:                      item,
+		ConditionExpression:       aws.String(condition),
+		ExpressionAttributeNames:  e.names,
+		ExpressionAttributeValues: e.values,
+	})
+	if err != nil {
+		return fmt.Errorf("put item failed: %w", err)
+	}
+	return nil
+}
+
+// SetAttribute sets a single attribute of the item with key using UpdateItem
+func (db *DDB) SetAttribute(ctx context.Context, tableName string, key map[string]types.AttributeValue, attr string, av types.AttributeValue) error {
+	var e exprBuilder
+	update := "SET " + e.condition(attr, "=", av)
+	_, err := db.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(tableName),
+		Key:                       key,
+		UpdateExpression:          aws.String(update),
+		ExpressionAttributeNames:  e.names,
+		ExpressionAttributeValues: e.values,
+	})
+	if err != nil {
+		return fmt.Errorf("update item failed: %w", err)
+	}
+	return nil
+}
+
+// RenameAttribute moves attribute from to to in the item with key, in one
+// UpdateItem that only applies while the item has from and lacks to. When
+// it doesn't, the *types.ConditionalCheckFailedException returned carries
+// the stored item, if any.
+func (db *DDB) RenameAttribute(ctx context.Context, tableName string, key map[string]types.AttributeValue, from, to string) error {
+	var e exprBuilder
+	fromName, toName := e.name(from), e.name(to)
+	_, err := db.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                           aws.String(tableName),
+		Key:                                 key,
+		UpdateExpression:                    aws.String(fmt.Sprintf("SET %s = %s REMOVE %s", toName, fromName, fromName)),
+		ConditionExpression:                 aws.String(fmt.Sprintf("attribute_exists(%s) AND attribute_not_exists(%s)", fromName, toName)),
+		ExpressionAttributeNames:            e.names,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	if err != nil {
+		return fmt.Errorf("update item failed: %w", err)
+	}
+	return nil
+}
+
 func (db *DDB) DeleteItem(ctx context.Context, tableName string, key map[string]types.AttributeValue) error {
 	_, err := db.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
 		TableName: aws.String(tableName),
@@ -229,10 +903,11 @@ func ItemToJSON(item map[string]types.AttributeValue) string {
 	return string(data)
 }
 
-// ItemToPrettyJSON converts a DynamoDB item to pretty-printed JSON
-func ItemToPrettyJSON(item map[string]types.AttributeValue) string {
+// ItemToPrettyJSON converts a DynamoDB item to JSON pretty-printed with
+// indent, the attributes in order first (see orderedJSON)
+func ItemToPrettyJSON(item map[string]types.AttributeValue, indent string, order []string) string {
 	simplified := attributeValueToInterface(item)
-	data, err := json.MarshalIndent(simplified, "", "  ")
+	data, err := json.MarshalIndent(orderedJSON(simplified, order), "", indent)
 	if err != nil {
 		return fmt.Sprintf("error: %v", err)
 	}
@@ -242,6 +917,13 @@ func ItemToPrettyJSON(item map[string]types.AttributeValue) string {
 // JSONToItem converts a JSON string to DynamoDB item
 // If originalItem is provided, it will preserve the original types for attributes without type hints
 func JSONToItem(jsonStr string, originalItem map[string]types.AttributeValue) (map[string]types.AttributeValue, error) {
+	// Be forgiving with hand-edited JSON: allow comments and trailing commas
+	jsonStr, err := stripJSONComments(jsonStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	jsonStr = stripTrailingCommas(jsonStr)
+
 	var data map[string]any
 	if err := json.Unmarshal([]byte(jsonStr), &data); err != nil {
 		return nil, fmt.Errorf("invalid JSON: %w", err)
@@ -254,6 +936,242 @@ func JSONToItem(jsonStr string, originalItem map[string]types.AttributeValue) (m
 	return interfaceToAttributeValueWithOriginal(processedData, originalItem), nil
 }
 
+// ItemToNativeJSON converts a DynamoDB item to indented DynamoDB JSON, where
+// every value carries its type, e.g. {"name": {"S": "x"}}
+func ItemToNativeJSON(item map[string]types.AttributeValue, indent string, order []string) string {
+	data, err := json.MarshalIndent(orderedJSON(itemToNative(item), order), "", indent)
+	if err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+	return string(data)
+}
+
+// NativeJSONToItem parses DynamoDB JSON into an item. Unlike JSONToItem no
+// types are inferred: each value must name its type.
+func NativeJSONToItem(jsonStr string) (map[string]types.AttributeValue, error) {
+	jsonStr, err := stripJSONComments(jsonStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	jsonStr = stripTrailingCommas(jsonStr)
+
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(jsonStr), &data); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	item := make(map[string]types.AttributeValue, len(data))
+	for k, raw := range data {
+		av, err := nativeToAttr(raw)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", k, err)
+		}
+		item[k] = av
+	}
+	return item, nil
+}
+
+// nativeToAttr parses a single DynamoDB JSON value such as {"N": "42"}
+func nativeToAttr(raw json.RawMessage) (types.AttributeValue, error) {
+	var typed map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &typed); err != nil || len(typed) != 1 {
+		return nil, fmt.Errorf("expected an object with a single type key, e.g. {\"S\": \"value\"}")
+	}
+	for typ, v := range typed {
+		var err error
+		switch typ {
+		case "S":
+			var s string
+			err = json.Unmarshal(v, &s)
+			return &types.AttributeValueMemberS{Value: s}, err
+		case "N":
+			var n string
+			if err = json.Unmarshal(v, &n); err == nil && !isNumber(n) {
+				err = fmt.Errorf("invalid number %q", n)
+			}
+			return &types.AttributeValueMemberN{Value: n}, err
+		case "B":
+			var b []byte
+			err = json.Unmarshal(v, &b)
+			return &types.AttributeValueMemberB{Value: b}, err
+		case "BOOL":
+			var b bool
+			err = json.Unmarshal(v, &b)
+			return &types.AttributeValueMemberBOOL{Value: b}, err
+		case "NULL":
+			return &types.AttributeValueMemberNULL{Value: true}, nil
+		case "SS":
+			var ss []string
+			err = json.Unmarshal(v, &ss)
+			return &types.AttributeValueMemberSS{Value: ss}, err
+		case "NS":
+			var ns []string
+			if err = json.Unmarshal(v, &ns); err == nil {
+				for _, n := range ns {
+					if !isNumber(n) {
+						return nil, fmt.Errorf("invalid number %q in NS", n)
+					}
+				}
+			}
+			return &types.AttributeValueMemberNS{Value: sortNumberSet(ns)}, err
+		case "BS":
+			var bs [][]byte
+			err = json.Unmarshal(v, &bs)
+			return &types.AttributeValueMemberBS{Value: bs}, err
+		case "L":
+			var elems []json.RawMessage
+			if err := json.Unmarshal(v, &elems); err != nil {
+				return nil, err
+			}
+			list := make([]types.AttributeValue, len(elems))
+			for i, elem := range elems {
+				if list[i], err = nativeToAttr(elem); err != nil {
+					return nil, fmt.Errorf("element %d: %w", i, err)
+				}
+			}
+			return &types.AttributeValueMemberL{Value: list}, nil
+		case "M":
+			var elems map[string]json.RawMessage
+			if err := json.Unmarshal(v, &elems); err != nil {
+				return nil, err
+			}
+			m := make(map[string]types.AttributeValue, len(elems))
+			for k, elem := range elems {
+				if m[k], err = nativeToAttr(elem); err != nil {
+					return nil, fmt.Errorf("%q: %w", k, err)
+				}
+			}
+			return &types.AttributeValueMemberM{Value: m}, nil
+		default:
+			return nil, fmt.Errorf("unknown type %q", typ)
+		}
+	}
+	return nil, nil
+}
+
+// isNumber reports whether s is a plain decimal number DynamoDB accepts
+func isNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil && !strings.ContainsAny(strings.ToLower(s), "abcdfinopstx_")
+}
+
+// sortNumberSet returns the numbers of a number set sorted numerically and
+// without duplicates, which DynamoDB rejects; "1" and "1.0" are the same
+// number, the first spelling is kept. Elements that aren't numbers sort last.
+// Numbers are compared exactly, as DynamoDB keeps 38 digits.
+func sortNumberSet(ns []string) []string {
+	type number struct {
+		s string
+		f *big.Rat
+	}
+	numbers := make([]number, 0, len(ns))
+	for _, n := range ns {
+		var f *big.Rat
+		// big.Rat also reads fractions like 1/2, which aren't numbers here
+		if t := strings.TrimSpace(n); !strings.Contains(t, "/") {
+			f, _ = new(big.Rat).SetString(t)
+		}
+		numbers = append(numbers, number{n, f})
+	}
+	slices.SortStableFunc(numbers, func(a, b number) int {
+		switch {
+		case a.f == nil && b.f == nil:
+			return strings.Compare(a.s, b.s)
+		case a.f == nil:
+			return 1
+		case b.f == nil:
+			return -1
+		}
+		return a.f.Cmp(b.f)
+	})
+	sorted := make([]string, 0, len(numbers))
+	for i, n := range numbers {
+		if i > 0 {
+			prev := numbers[i-1]
+			if n.f != nil && prev.f != nil && n.f.Cmp(prev.f) == 0 || n.f == nil && prev.f == nil && n.s == prev.s {
+				continue
+			}
+		}
+		sorted = append(sorted, n.s)
+	}
+	return sorted
+}
+
+// stripJSONComments removes // line comments and /* */ block comments from
+// JSON text. String literals are left untouched, so "http://x" survives.
+func stripJSONComments(s string) (string, error) {
+	var b strings.Builder
+	inString := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			b.WriteByte(c)
+			if c == '\\' && i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			b.WriteByte(c)
+		case c == '/' && i+1 < len(s) && s[i+1] == '/':
+			// Skip to end of line, keeping the newline
+			for i+1 < len(s) && s[i+1] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(s) && s[i+1] == '*':
+			end := strings.Index(s[i+2:], "*/")
+			if end == -1 {
+				line := strings.Count(s[:i], "\n") + 1
+				return "", fmt.Errorf("unterminated /* comment on line %d", line)
+			}
+			// Keep newlines so JSON error offsets stay close to the original
+			b.WriteString(strings.Repeat("\n", strings.Count(s[i:i+2+end], "\n")))
+			b.WriteByte(' ')
+			i += end + 3
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
+
+// stripTrailingCommas removes commas directly followed (ignoring whitespace)
+// by a closing } or ], outside of string literals
+func stripTrailingCommas(s string) string {
+	var b strings.Builder
+	inString := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			b.WriteByte(c)
+			if c == '\\' && i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		if c == ',' {
+			rest := strings.TrimLeft(s[i+1:], " \t\r\n")
+			if strings.HasPrefix(rest, "}") || strings.HasPrefix(rest, "]") {
+				continue
+			}
+		}
+		if c == '"' {
+			inString = true
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
 // processTypeHints processes attribute names with type hints (e.g., "name<S>", "age<N>")
 // and returns a new map with the type hints applied and removed from attribute names
 func processTypeHints(data map[string]any) (map[string]any, error) {
@@ -274,14 +1192,39 @@ func processTypeHints(data map[string]any) (map[string]any, error) {
 
 			result[cleanKey] = convertedValue
 		} else {
-			// No type hint, keep as-is
-			result[key] = value
+			// No type hint, but nested maps and lists may have some
+			nested, err := processNestedTypeHints(value)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = nested
 		}
 	}
 
 	return result, nil
 }
 
+// processNestedTypeHints applies the type hints in the maps within value,
+// descending into lists
+func processNestedTypeHints(value any) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		return processTypeHints(v)
+	case []any:
+		list := make([]any, len(v))
+		for i, elem := range v {
+			processed, err := processNestedTypeHints(elem)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+			list[i] = processed
+		}
+		return list, nil
+	default:
+		return value, nil
+	}
+}
+
 // convertValueWithTypeHint converts a value to a specific format based on the DynamoDB type hint
 func convertValueWithTypeHint(value any, typeHint string) (any, error) {
 	switch strings.ToUpper(typeHint) {
@@ -320,19 +1263,24 @@ func convertValueWithTypeHint(value any, typeHint string) (any, error) {
 
 	case "L":
 		// List type - use special marker to prevent conversion back to sets
+		var list []any
 		switch v := value.(type) {
 		case []any:
-			return map[string]any{"__L": v}, nil
+			list = v
 		case string:
 			// Try to parse as JSON array
-			var list []any
 			if err := json.Unmarshal([]byte(v), &list); err != nil {
 				return nil, fmt.Errorf("cannot parse list: %w", err)
 			}
-			return map[string]any{"__L": list}, nil
 		default:
-			return map[string]any{"__L": []any{v}}, nil
+			list = []any{v}
+		}
+		// Apply type hints inside elements, e.g. [{"count<N>": "5"}]
+		processed, err := processNestedTypeHints(list)
+		if err != nil {
+			return nil, err
 		}
+		return map[string]any{"__L": processed}, nil
 
 	case "M":
 		// Map type
@@ -384,7 +1332,7 @@ func convertValueWithTypeHint(value any, typeHint string) (any, error) {
 			for i, item := range v {
 				ns[i] = fmt.Sprintf("%v", item)
 			}
-			return map[string]any{"__NS": ns}, nil
+			return map[string]any{"__NS": sortNumberSet(ns)}, nil
 		case string:
 			// Try to parse as JSON array
 			var list []any
@@ -396,59 +1344,136 @@ func convertValueWithTypeHint(value any, typeHint string) (any, error) {
 			for i, item := range list {
 				ns[i] = fmt.Sprintf("%v", item)
 			}
-			return map[string]any{"__NS": ns}, nil
+			return map[string]any{"__NS": sortNumberSet(ns)}, nil
 		default:
 			return map[string]any{"__NS": []string{fmt.Sprintf("%v", v)}}, nil
 		}
 
 	case "B":
-		// Binary type
+		// Binary type, base64 encoded like the editor shows it
 		switch v := value.(type) {
 		case []byte:
 			return v, nil
 		case string:
-			// Assume base64 encoded
-			return []byte(v), nil
+			return decodeBase64(v)
 		default:
-			return []byte(fmt.Sprintf("%v", v)), nil
+			return nil, fmt.Errorf("cannot convert %v to binary: expected a base64 string", v)
 		}
 
 	case "BS":
-		// Binary Set
+		// Binary Set of base64 encoded elements
+		var list []any
 		switch v := value.(type) {
 		case []any:
-			bs := make([][]byte, len(v))
-			for i, item := range v {
-				if b, ok := item.([]byte); ok {
-					bs[i] = b
-				} else {
-					bs[i] = []byte(fmt.Sprintf("%v", item))
-				}
-			}
-			return map[string]any{"__BS": bs}, nil
+			list = v
 		case string:
-			// Try to parse as JSON array
-			var list []any
+			// Try to parse as JSON array, else treat as single-element set
 			if err := json.Unmarshal([]byte(v), &list); err != nil {
-				// Treat as single-element set
-				return map[string]any{"__BS": [][]byte{[]byte(v)}}, nil
+				list = []any{v}
 			}
-			bs := make([][]byte, len(list))
-			for i, item := range list {
-				if b, ok := item.([]byte); ok {
-					bs[i] = b
-				} else {
-					bs[i] = []byte(fmt.Sprintf("%v", item))
+		default:
+			list = []any{v}
+		}
+		bs := make([][]byte, len(list))
+		for i, item := range list {
+			switch elem := item.(type) {
+			case []byte:
+				bs[i] = elem
+			case string:
+				b, err := decodeBase64(elem)
+				if err != nil {
+					return nil, fmt.Errorf("element %d: %w", i, err)
 				}
+				bs[i] = b
+			default:
+				return nil, fmt.Errorf("element %d: cannot convert %v to binary: expected a base64 string", i, elem)
 			}
-			return map[string]any{"__BS": bs}, nil
-		default:
-			return map[string]any{"__BS": [][]byte{[]byte(fmt.Sprintf("%v", v))}}, nil
 		}
+		return map[string]any{"__BS": bs}, nil
 
 	default:
-		return nil, fmt.Errorf("unknown type hint: %s", typeHint)
+		if suggestion := suggestTypeHint(typeHint); suggestion != "" {
+			return nil, fmt.Errorf("unknown type hint '%s', did you mean '%s'?", typeHint, suggestion)
+		}
+		return nil, fmt.Errorf("unknown type hint '%s' (valid: %s)", typeHint, strings.Join(typeHints, ", "))
+	}
+}
+
+// decodeBase64 decodes a binary value as shown in the editor
+func decodeBase64(s string) ([]byte, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 %q: %w", s, err)
+	}
+	return b, nil
+}
+
+// typeHints are the valid <TYPE> hints
+var typeHints = []string{"S", "N", "BOOL", "NULL", "L", "M", "SS", "NS", "B", "BS"}
+
+// typeHintAliases maps common type names to the hint meant
+var typeHintAliases = map[string]string{
+	"STR":     "S",
+	"STRING":  "S",
+	"NUM":     "N",
+	"NUMBER":  "N",
+	"INT":     "N",
+	"INTEGER": "N",
+	"FLOAT":   "N",
+	"BOOLEAN": "BOOL",
+	"NIL":     "NULL",
+	"NONE":    "NULL",
+	"LIST":    "L",
+	"ARRAY":   "L",
+	"MAP":     "M",
+	"OBJECT":  "M",
+	"BIN":     "B",
+	"BINARY":  "B",
+	"BYTES":   "B",
+}
+
+// suggestTypeHint returns the valid hint closest to a mistyped one, or "" if
+// none is close
+func suggestTypeHint(hint string) string {
+	hint = strings.ToUpper(hint)
+	if alias, ok := typeHintAliases[hint]; ok {
+		return alias
+	}
+	best, bestDist := "", 3 // suggest only within two edits
+	for _, valid := range typeHints {
+		d := editDistance(hint, valid)
+		// On a tie, prefer the hint with the same first letter
+		if d < bestDist || (d == bestDist && sameInitial(valid, hint) && !sameInitial(best, hint)) {
+			best, bestDist = valid, d
+		}
+	}
+	return best
+}
+
+// sameInitial reports whether a and b start with the same letter
+func sameInitial(a, b string) bool {
+	return a != "" && b != "" && a[0] == b[0]
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
 	}
+	return prev[len(b)]
 }
 
 func attributeValueToInterface(item map[string]types.AttributeValue) map[string]any {
@@ -480,7 +1505,7 @@ func attrToInterface(av types.AttributeValue) any {
 	case *types.AttributeValueMemberSS:
 		return v.Value
 	case *types.AttributeValueMemberNS:
-		return v.Value
+		return sortNumberSet(v.Value)
 	case *types.AttributeValueMemberB:
 		return v.Value
 	case *types.AttributeValueMemberBS:
@@ -490,6 +1515,51 @@ func attrToInterface(av types.AttributeValue) any {
 	}
 }
 
+// itemToNative converts an item to DynamoDB JSON
+func itemToNative(item map[string]types.AttributeValue) map[string]any {
+	native := make(map[string]any, len(item))
+	for k, v := range item {
+		native[k] = attrToNative(v)
+	}
+	return native
+}
+
+// attrToNative converts an AttributeValue to DynamoDB JSON, e.g. {"S": "x"}
+func attrToNative(av types.AttributeValue) any {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		return map[string]any{"S": v.Value}
+	case *types.AttributeValueMemberN:
+		return map[string]any{"N": v.Value}
+	case *types.AttributeValueMemberBOOL:
+		return map[string]any{"BOOL": v.Value}
+	case *types.AttributeValueMemberNULL:
+		return map[string]any{"NULL": true}
+	case *types.AttributeValueMemberB:
+		return map[string]any{"B": v.Value}
+	case *types.AttributeValueMemberSS:
+		return map[string]any{"SS": v.Value}
+	case *types.AttributeValueMemberNS:
+		return map[string]any{"NS": v.Value}
+	case *types.AttributeValueMemberBS:
+		return map[string]any{"BS": v.Value}
+	case *types.AttributeValueMemberL:
+		list := make([]any, len(v.Value))
+		for i, item := range v.Value {
+			list[i] = attrToNative(item)
+		}
+		return map[string]any{"L": list}
+	case *types.AttributeValueMemberM:
+		m := make(map[string]any, len(v.Value))
+		for k, item := range v.Value {
+			m[k] = attrToNative(item)
+		}
+		return map[string]any{"M": m}
+	default:
+		return nil
+	}
+}
+
 func interfaceToAttributeValue(data map[string]any) map[string]types.AttributeValue {
 	result := make(map[string]types.AttributeValue)
 	for k, v := range data {
@@ -547,13 +1617,16 @@ func valueToAttrWithOriginal(v any, originalAttr types.AttributeValue) types.Att
 				for i, item := range val {
 					ns[i] = fmt.Sprintf("%v", item)
 				}
-				return &types.AttributeValueMemberNS{Value: ns}
+				return &types.AttributeValueMemberNS{Value: sortNumberSet(ns)}
 			case *types.AttributeValueMemberBS:
-				// Original was BS, convert array to BS
+				// Original was BS, convert array to BS, decoding the
+				// base64 the editor shows
 				bs := make([][]byte, len(val))
 				for i, item := range val {
 					if b, ok := item.([]byte); ok {
 						bs[i] = b
+					} else if b, err := decodeBase64(fmt.Sprintf("%v", item)); err == nil {
+						bs[i] = b
 					} else {
 						bs[i] = []byte(fmt.Sprintf("%v", item))
 					}
@@ -585,7 +1658,7 @@ func valueToAttrWithOriginal(v any, originalAttr types.AttributeValue) types.Att
 		}
 		if ns, ok := val["__NS"]; ok {
 			if nsSlice, ok := ns.([]string); ok {
-				return &types.AttributeValueMemberNS{Value: nsSlice}
+				return &types.AttributeValueMemberNS{Value: sortNumberSet(nsSlice)}
 			}
 		}
 		if bs, ok := val["__BS"]; ok {
@@ -633,14 +1706,77 @@ func ParseKeyValue(keyValue string) (string, types.AttributeValue, error) {
 	}
 	key := strings.TrimSpace(parts[0])
 	value := strings.TrimSpace(parts[1])
+	return key, inferAttributeValue(value), nil
+}
+
+// ParseTypedKeyValue parses key=value[:TYPE]. Without a type suffix the type
+// is inferred like ParseKeyValue; a suffix that isn't a type hint, as in
+// host=db:5432, is part of the value.
+func ParseTypedKeyValue(s string) (string, types.AttributeValue, error) {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || strings.TrimSpace(name) == "" {
+		return "", nil, fmt.Errorf("invalid key=value format: %s", s)
+	}
+	idx := strings.LastIndex(value, ":")
+	if idx == -1 || !slices.Contains(typeHints, value[idx+1:]) {
+		return ParseKeyValue(s)
+	}
+	hint := value[idx+1:]
+	value = value[:idx]
+	if hint == "N" && !isNumber(value) {
+		return "", nil, fmt.Errorf("%s: %q is not a number", name, value)
+	}
+	return ParseAssignment(name+"<"+hint+">="+value, nil)
+}
 
+// ParseAssignment parses attr=value for setting a single attribute. Plain
+// values are inferred like ParseKeyValue; JSON values (lists, maps, booleans,
+// null, quoted strings) keep their JSON type; a <TYPE> hint on the name
+// forces the type, e.g. tags<SS>=["a","b"]. The attribute's current type in
+// original is kept where the value is ambiguous, as in the editor.
+func ParseAssignment(s string, original map[string]types.AttributeValue) (string, types.AttributeValue, error) {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return "", nil, fmt.Errorf("invalid attr=value format: %s", s)
+	}
+	name = strings.TrimSpace(name)
+	value = strings.TrimSpace(value)
+	if name == "" {
+		return "", nil, fmt.Errorf("empty attribute name")
+	}
+
+	var v any = value
+	isJSON := false
+	if json.Valid([]byte(value)) {
+		dec := json.NewDecoder(strings.NewReader(value))
+		dec.UseNumber()
+		isJSON = dec.Decode(&v) == nil
+	}
+	hinted := strings.HasSuffix(name, ">") && strings.Contains(name, "<")
+	if !isJSON && !hinted {
+		return name, inferAttributeValue(value), nil
+	}
+
+	processed, err := processTypeHints(map[string]any{name: v})
+	if err != nil {
+		return "", nil, err
+	}
+	for k, v := range interfaceToAttributeValueWithOriginal(processed, original) {
+		return k, v, nil
+	}
+	return "", nil, fmt.Errorf("invalid attr=value format: %s", s)
+}
+
+// inferAttributeValue returns value as a Number if it looks like one,
+// otherwise as a String
+func inferAttributeValue(value string) types.AttributeValue {
 	// Try to determine if it's a number
 	if _, err := fmt.Sscanf(value, "%f", new(float64)); err == nil && !strings.Contains(value, "\"") {
-		return key, &types.AttributeValueMemberN{Value: value}, nil
+		return &types.AttributeValueMemberN{Value: value}
 	}
 
 	// Default to string
-	return key, &types.AttributeValueMemberS{Value: value}, nil
+	return &types.AttributeValueMemberS{Value: value}
 }
 
 // BuildKey builds a DynamoDB key from partition and optional sort key
@@ -684,9 +1820,9 @@ func AttributeValueToString(av types.AttributeValue) string {
 }
 
 // ItemToDataTypes converts a DynamoDB item to a pretty-printed JSON-like structure showing data types
-func ItemToDataTypes(item map[string]types.AttributeValue) string {
+func ItemToDataTypes(item map[string]types.AttributeValue, indent string) string {
 	typeMap := attributeValueToTypeMap(item)
-	data, err := json.MarshalIndent(typeMap, "", "  ")
+	data, err := json.MarshalIndent(typeMap, "", indent)
 	if err != nil {
 		return fmt.Sprintf("error: %v", err)
 	}
@@ -737,3 +1873,81 @@ func attrToType(av types.AttributeValue) any {
 		return "Unknown"
 	}
 }
+
+// ItemSizeLimit is the maximum size of a DynamoDB item in bytes
+const ItemSizeLimit = 400 * 1024
+
+// AttributeSize returns the size in bytes DynamoDB counts for an attribute:
+// the length of its name plus the size of its value
+func AttributeSize(name string, av types.AttributeValue) int {
+	return len(name) + attrValueSize(av)
+}
+
+// ItemSize returns the size in bytes DynamoDB counts for an item
+func ItemSize(item map[string]types.AttributeValue) int {
+	size := 0
+	for name, av := range item {
+		size += AttributeSize(name, av)
+	}
+	return size
+}
+
+// attrValueSize follows DynamoDB's item size rules: strings and binaries
+// count their bytes, numbers about one byte per two significant digits plus
+// one, booleans and nulls one byte, and lists and maps 3 bytes plus one byte
+// per element.
+func attrValueSize(av types.AttributeValue) int {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		return len(v.Value)
+	case *types.AttributeValueMemberN:
+		return numberSize(v.Value)
+	case *types.AttributeValueMemberB:
+		return len(v.Value)
+	case *types.AttributeValueMemberBOOL, *types.AttributeValueMemberNULL:
+		return 1
+	case *types.AttributeValueMemberSS:
+		size := 0
+		for _, s := range v.Value {
+			size += len(s)
+		}
+		return size
+	case *types.AttributeValueMemberNS:
+		size := 0
+		for _, n := range v.Value {
+			size += numberSize(n)
+		}
+		return size
+	case *types.AttributeValueMemberBS:
+		size := 0
+		for _, b := range v.Value {
+			size += len(b)
+		}
+		return size
+	case *types.AttributeValueMemberL:
+		size := 3
+		for _, item := range v.Value {
+			size += 1 + attrValueSize(item)
+		}
+		return size
+	case *types.AttributeValueMemberM:
+		size := 3
+		for name, item := range v.Value {
+			size += 1 + AttributeSize(name, item)
+		}
+		return size
+	default:
+		return 0
+	}
+}
+
+// numberSize approximates the stored size of a number: one byte per two
+// significant digits, plus one
+func numberSize(n string) int {
+	mantissa, _, _ := strings.Cut(strings.ToLower(n), "e")
+	digits := strings.Trim(strings.NewReplacer("-", "", "+", "", ".", "").Replace(mantissa), "0")
+	if digits == "" {
+		return 1
+	}
+	return (len(digits)+1)/2 + 1
+}
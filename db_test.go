@@ -0,0 +1,315 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	streamstypes "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+)
+
+func TestStripJSONComments(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"no comments", `{"a": 1}`, `{"a": 1}`},
+		{"line comment", "{\"a\": 1 // one\n}", "{\"a\": 1 \n}"},
+		{"block comment", `{"a": /* one */ 1}`, `{"a":   1}`},
+		{"block comment keeps newlines", "{/* a\nb */\"a\": 1}", "{\n \"a\": 1}"},
+		{"line comment in string", `{"url": "http://x"}`, `{"url": "http://x"}`},
+		{"block comment in string", `{"a": "/* x */"}`, `{"a": "/* x */"}`},
+		{"escaped quote in string", `{"a": "\" // x"} // c`, `{"a": "\" // x"} `},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := stripJSONComments(tt.in)
+			if err != nil {
+				t.Fatalf("stripJSONComments(%q) error: %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("stripJSONComments(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripJSONCommentsUnterminated(t *testing.T) {
+	_, err := stripJSONComments("{\n\"a\": 1 /* never closed\n}")
+	if err == nil || !strings.Contains(err.Error(), "unterminated /* comment on line 2") {
+		t.Errorf("stripJSONComments error = %v, want unterminated comment on line 2", err)
+	}
+}
+
+func TestJSONToItemComments(t *testing.T) {
+	item, err := JSONToItem(`{
+		// the key
+		"pk": "a // not a comment", /* inline */
+		"tags": ["x", "y",],
+	}`, nil)
+	if err != nil {
+		t.Fatalf("JSONToItem error: %v", err)
+	}
+	if got := GetKeyValue(item, "pk"); got != "a // not a comment" {
+		t.Errorf("pk = %q, want %q", got, "a // not a comment")
+	}
+}
+
+func TestSuggestTypeHint(t *testing.T) {
+	tests := []struct {
+		hint string
+		want string
+	}{
+		// Aliases and typos
+		{"NUM", "N"},
+		{"INTEGER", "N"},
+		{"STRING", "S"},
+		{"BOO", "BOOL"},
+		{"SSS", "SS"},
+		{"NSS", "NS"},
+		// Case differences
+		{"nS", "NS"},
+		{"num", "N"},
+		{"bool", "BOOL"},
+		{"Nul", "NULL"},
+		// Nothing close
+		{"XYZ", ""},
+		{"DATE", ""},
+		{"TIMESTAMP", ""},
+	}
+	for _, tt := range tests {
+		if got := suggestTypeHint(tt.hint); got != tt.want {
+			t.Errorf("suggestTypeHint(%q) = %q, want %q", tt.hint, got, tt.want)
+		}
+	}
+}
+
+func TestUnknownTypeHintError(t *testing.T) {
+	tests := []struct {
+		json string
+		want string
+	}{
+		{`{"age<NUM>": 3}`, "did you mean 'N'?"},
+		{`{"tags<nSS>": ["a"]}`, "did you mean 'NS'?"},
+		{`{"when<DATE>": "x"}`, "valid: S, N, BOOL"},
+	}
+	for _, tt := range tests {
+		_, err := JSONToItem(tt.json, nil)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("JSONToItem(%s) error = %v, want it to contain %q", tt.json, err, tt.want)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"N", "", 1},
+		{"NS", "NS", 0},
+		{"NUM", "N", 2},
+		{"BOOL", "BOL", 1},
+		{"SS", "NS", 1},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestJSONToItemNestedTypeHints(t *testing.T) {
+	item, err := JSONToItem(`{
+		"pk": "order-1",
+		"lines": [
+			{"sku": "A1", "qty<N>": "2", "tags<SS>": ["red", "big"]},
+			{"sku": "B2", "qty<N>": "10", "sizes<NS>": ["1", "3"]},
+			"note"
+		]
+	}`, nil)
+	if err != nil {
+		t.Fatalf("JSONToItem error: %v", err)
+	}
+	lines, ok := item["lines"].(*types.AttributeValueMemberL)
+	if !ok || len(lines.Value) != 3 {
+		t.Fatalf("lines = %#v, want a list of 3", item["lines"])
+	}
+	first, ok := lines.Value[0].(*types.AttributeValueMemberM)
+	if !ok {
+		t.Fatalf("lines[0] = %#v, want a map", lines.Value[0])
+	}
+	if qty, ok := first.Value["qty"].(*types.AttributeValueMemberN); !ok || qty.Value != "2" {
+		t.Errorf("lines[0].qty = %#v, want N 2", first.Value["qty"])
+	}
+	if tags, ok := first.Value["tags"].(*types.AttributeValueMemberSS); !ok || !slices.Equal(tags.Value, []string{"red", "big"}) {
+		t.Errorf("lines[0].tags = %#v, want SS [red big]", first.Value["tags"])
+	}
+	if _, ok := first.Value["qty<N>"]; ok {
+		t.Errorf("lines[0] kept the hinted name qty<N>")
+	}
+	second := lines.Value[1].(*types.AttributeValueMemberM)
+	if qty, ok := second.Value["qty"].(*types.AttributeValueMemberN); !ok || qty.Value != "10" {
+		t.Errorf("lines[1].qty = %#v, want N 10", second.Value["qty"])
+	}
+	if sizes, ok := second.Value["sizes"].(*types.AttributeValueMemberNS); !ok || !slices.Equal(sizes.Value, []string{"1", "3"}) {
+		t.Errorf("lines[1].sizes = %#v, want NS [1 3]", second.Value["sizes"])
+	}
+	if _, ok := lines.Value[2].(*types.AttributeValueMemberS); !ok {
+		t.Errorf("lines[2] = %#v, want S", lines.Value[2])
+	}
+}
+
+func TestJSONToItemNestedTypeHintError(t *testing.T) {
+	_, err := JSONToItem(`{"lines": [{"ok": 1}, {"on<BOOL>": 3}]}`, nil)
+	if err == nil || !strings.Contains(err.Error(), "element 1") {
+		t.Errorf("JSONToItem error = %v, want it to name element 1", err)
+	}
+}
+
+func TestParseTypedKeyValue(t *testing.T) {
+	tests := []struct {
+		in      string
+		name    string
+		want    types.AttributeValue
+		wantErr bool
+	}{
+		{in: "pk=1", name: "pk", want: &types.AttributeValueMemberN{Value: "1"}},
+		{in: "pk=1:S", name: "pk", want: &types.AttributeValueMemberS{Value: "1"}},
+		{in: "age=42:N", name: "age", want: &types.AttributeValueMemberN{Value: "42"}},
+		{in: "on=true:BOOL", name: "on", want: &types.AttributeValueMemberBOOL{Value: true}},
+		{in: "v=null:NULL", name: "v", want: &types.AttributeValueMemberNULL{Value: true}},
+		{in: `tags=["a","b"]:SS`, name: "tags", want: &types.AttributeValueMemberSS{Value: []string{"a", "b"}}},
+		{in: "nums=[1,2]:NS", name: "nums", want: &types.AttributeValueMemberNS{Value: []string{"1", "2"}}},
+		// A suffix that isn't a type hint is part of the value
+		{in: "host=db:5432", name: "host", want: &types.AttributeValueMemberS{Value: "db:5432"}},
+		{in: "note=a:b:S", name: "note", want: &types.AttributeValueMemberS{Value: "a:b"}},
+		{in: "name=", name: "name", want: &types.AttributeValueMemberS{Value: ""}},
+		{in: "age=x:N", wantErr: true},
+		{in: "=x", wantErr: true},
+		{in: "noeq", wantErr: true},
+	}
+	for _, tt := range tests {
+		name, av, err := ParseTypedKeyValue(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseTypedKeyValue(%q) = %q, %#v, want an error", tt.in, name, av)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseTypedKeyValue(%q) error: %v", tt.in, err)
+			continue
+		}
+		if name != tt.name || !reflect.DeepEqual(av, tt.want) {
+			t.Errorf("ParseTypedKeyValue(%q) = %q, %#v, want %q, %#v", tt.in, name, av, tt.name, tt.want)
+		}
+	}
+}
+
+func TestShardStartsAfter(t *testing.T) {
+	shard := func(start, end string) streamstypes.Shard {
+		r := &streamstypes.SequenceNumberRange{StartingSequenceNumber: aws.String(start)}
+		if end != "" {
+			r.EndingSequenceNumber = aws.String(end)
+		}
+		return streamstypes.Shard{SequenceNumberRange: r}
+	}
+	tests := []struct {
+		name string
+		a, b streamstypes.Shard
+		want bool
+	}{
+		{"open beats closed", shard("100", ""), shard("900", "950"), true},
+		{"closed loses to open", shard("900", "950"), shard("100", ""), false},
+		{"no range is open", streamstypes.Shard{}, shard("900", "950"), true},
+		{"later start", shard("200", ""), shard("100", ""), true},
+		{"earlier start", shard("100", ""), shard("200", ""), false},
+		{"longer number is later", shard("1000", ""), shard("999", ""), true},
+		{"shorter number is earlier", shard("999", "1000"), shard("1000", "1001"), false},
+		{"same start", shard("100", ""), shard("100", ""), false},
+	}
+	for _, tt := range tests {
+		if got := shardStartsAfter(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: shardStartsAfter = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStreamValue(t *testing.T) {
+	tests := []struct {
+		in   streamstypes.AttributeValue
+		want types.AttributeValue
+	}{
+		{&streamstypes.AttributeValueMemberS{Value: "a"}, &types.AttributeValueMemberS{Value: "a"}},
+		{&streamstypes.AttributeValueMemberN{Value: "1.5"}, &types.AttributeValueMemberN{Value: "1.5"}},
+		{&streamstypes.AttributeValueMemberB{Value: []byte{1, 2}}, &types.AttributeValueMemberB{Value: []byte{1, 2}}},
+		{&streamstypes.AttributeValueMemberBOOL{Value: true}, &types.AttributeValueMemberBOOL{Value: true}},
+		{&streamstypes.AttributeValueMemberNULL{Value: true}, &types.AttributeValueMemberNULL{Value: true}},
+		{&streamstypes.AttributeValueMemberSS{Value: []string{"a", "b"}}, &types.AttributeValueMemberSS{Value: []string{"a", "b"}}},
+		{&streamstypes.AttributeValueMemberNS{Value: []string{"1", "2"}}, &types.AttributeValueMemberNS{Value: []string{"1", "2"}}},
+		{&streamstypes.AttributeValueMemberBS{Value: [][]byte{{1}}}, &types.AttributeValueMemberBS{Value: [][]byte{{1}}}},
+		{
+			&streamstypes.AttributeValueMemberM{Value: map[string]streamstypes.AttributeValue{
+				"n": &streamstypes.AttributeValueMemberN{Value: "3"},
+			}},
+			&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+				"n": &types.AttributeValueMemberN{Value: "3"},
+			}},
+		},
+		{
+			&streamstypes.AttributeValueMemberL{Value: []streamstypes.AttributeValue{
+				&streamstypes.AttributeValueMemberS{Value: "x"},
+				&streamstypes.AttributeValueMemberL{Value: []streamstypes.AttributeValue{
+					&streamstypes.AttributeValueMemberBOOL{Value: false},
+				}},
+			}},
+			&types.AttributeValueMemberL{Value: []types.AttributeValue{
+				&types.AttributeValueMemberS{Value: "x"},
+				&types.AttributeValueMemberL{Value: []types.AttributeValue{
+					&types.AttributeValueMemberBOOL{Value: false},
+				}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		if got := streamValue(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("streamValue(%#v) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSortNumberSet(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{"sorted numerically", []string{"10", "9", "-1", "1.5"}, []string{"-1", "1.5", "9", "10"}},
+		{"same number, first spelling kept", []string{"1.0", "2", "1"}, []string{"1.0", "2"}},
+		{"exponent", []string{"1e2", "100", "99"}, []string{"99", "1e2"}},
+		{"23 digits differing in the last", []string{"12345678901234567890124", "12345678901234567890123"},
+			[]string{"12345678901234567890123", "12345678901234567890124"}},
+		{"38 digits differing in the last", []string{
+			"0.00000000000000000000000000000000000002",
+			"0.00000000000000000000000000000000000001",
+		}, []string{
+			"0.00000000000000000000000000000000000001",
+			"0.00000000000000000000000000000000000002",
+		}},
+		{"not numbers sort last", []string{"x", "2", "1/2", "x"}, []string{"2", "1/2", "x"}},
+	}
+	for _, tt := range tests {
+		if got := sortNumberSet(tt.in); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: sortNumberSet(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,490 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// displayOptions control how items are presented in the list and item view.
+// They never change stored data or the content opened in the editor.
+type displayOptions struct {
+	// groupNumbers formats Number values with thousands separators
+	groupNumbers bool
+
+	// timestamps shows Number attributes whose name matches one of
+	// timestampAttrs (glob patterns) as dates next to the raw value
+	timestamps     bool
+	timestampAttrs []string
+
+	// colorTypes colors the list's JSON column by value type
+	colorTypes bool
+
+	// attrCount shows a column with the number of top-level attributes of
+	// each item in the list
+	attrCount bool
+
+	// hideEmpty leaves empty strings, lists, and maps and nulls out of the
+	// item view
+	hideEmpty bool
+
+	// sortKeys are the composite sort key formats by table name
+	sortKeys map[string]SortKeyFormat
+
+	// density and keyWidth lay out the list columns
+	density  density
+	keyWidth int
+
+	// indent is the indentation of the item view's JSON
+	indent string
+
+	// order lists the attributes shown first, in order, for the current
+	// table: its keys, then its display_order. nil sorts every attribute
+	// by name.
+	order []string
+}
+
+// density is how much room the list gives the key columns
+type density int
+
+const (
+	densityNormal      density = iota
+	densityComfortable         // wider key columns
+	densityCompact             // key columns only, no JSON
+)
+
+// String returns the density's name as used in the config
+func (d density) String() string {
+	switch d {
+	case densityComfortable:
+		return "comfortable"
+	case densityCompact:
+		return "compact"
+	default:
+		return "normal"
+	}
+}
+
+// next returns the density the D key switches to
+func (d density) next() density {
+	switch d {
+	case densityNormal:
+		return densityComfortable
+	case densityComfortable:
+		return densityCompact
+	default:
+		return densityNormal
+	}
+}
+
+// parseDensity returns the density named s, defaulting to normal
+func parseDensity(s string) density {
+	for _, d := range []density{densityComfortable, densityCompact} {
+		if strings.EqualFold(s, d.String()) {
+			return d
+		}
+	}
+	return densityNormal
+}
+
+// defaultKeyWidth is the width of the key columns in normal density
+const defaultKeyWidth = 20
+
+// newDisplayOptions returns the display options configured by cfg
+func newDisplayOptions(cfg *Config) displayOptions {
+	o := displayOptions{
+		groupNumbers:   cfg.GroupNumbers,
+		timestamps:     cfg.ShowTimestamps,
+		timestampAttrs: cfg.TimestampAttrs,
+		colorTypes:     cfg.ColorTypes,
+		sortKeys:       cfg.SortKeys,
+		density:        parseDensity(cfg.Density),
+		keyWidth:       cfg.KeyWidth,
+		indent:         cfg.indent(),
+	}
+	if o.keyWidth <= 0 {
+		o.keyWidth = defaultKeyWidth
+	}
+	if len(o.timestampAttrs) == 0 {
+		o.timestampAttrs = defaultTimestampAttrs
+	}
+	return o
+}
+
+// defaultTimestampAttrs are the attribute name patterns treated as epoch
+// timestamps when the config doesn't set timestamp_attrs
+var defaultTimestampAttrs = []string{"*_at", "ttl", "timestamp"}
+
+// item returns the simplified item with the display options applied
+func (o displayOptions) item(item map[string]types.AttributeValue) map[string]any {
+	result := make(map[string]any, len(item))
+	for k, v := range item {
+		if n, ok := v.(*types.AttributeValueMemberN); ok && o.isTimestamp(k) {
+			if ts, ok := epochToDate(n.Value); ok {
+				result[k] = n.Value + " (" + ts + ")"
+				continue
+			}
+		}
+		result[k] = o.attr(v)
+	}
+	return result
+}
+
+// isTimestamp reports whether an attribute holds an epoch timestamp
+func (o displayOptions) isTimestamp(name string) bool {
+	if !o.timestamps {
+		return false
+	}
+	for _, pattern := range o.timestampAttrs {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// epochToDate formats a Unix timestamp as RFC3339. Values too large to be
+// seconds in a plausible range (past year 5000) are taken as milliseconds.
+func epochToDate(n string) (string, bool) {
+	f, err := strconv.ParseFloat(n, 64)
+	if err != nil {
+		return "", false
+	}
+	if f >= 1e11 || f <= -1e11 {
+		return time.UnixMilli(int64(f)).Format(time.RFC3339), true
+	}
+	return time.Unix(int64(f), 0).Format(time.RFC3339), true
+}
+
+func (o displayOptions) attr(av types.AttributeValue) any {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberN:
+		return o.number(v.Value)
+	case *types.AttributeValueMemberNS:
+		// Numbers, sorted, rather than the strings the API returns
+		ns := sortNumberSet(v.Value)
+		list := make([]any, len(ns))
+		for i, n := range ns {
+			list[i] = o.number(n)
+		}
+		return list
+	case *types.AttributeValueMemberL:
+		list := make([]any, len(v.Value))
+		for i, item := range v.Value {
+			list[i] = o.attr(item)
+		}
+		return list
+	case *types.AttributeValueMemberM:
+		return o.item(v.Value)
+	default:
+		return attrToInterface(av)
+	}
+}
+
+func (o displayOptions) number(n string) any {
+	if o.groupNumbers {
+		if grouped, ok := groupDigits(n); ok {
+			return grouped
+		}
+	}
+	return json.Number(n)
+}
+
+// json returns the item as compact JSON for the list
+func (o displayOptions) json(item map[string]types.AttributeValue) string {
+	data, err := json.Marshal(orderedJSON(o.item(item), o.order))
+	if err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+	return string(data)
+}
+
+// prettyJSON returns the item as indented JSON, with multi-line strings
+// broken into lines
+func (o displayOptions) prettyJSON(item map[string]types.AttributeValue) string {
+	data, err := json.MarshalIndent(orderedJSON(o.item(item), o.order), "", o.indent)
+	if err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+	return breakMultilineStrings(string(data), o.indent)
+}
+
+// orderedObject is a JSON object whose keys are written in a given order
+// rather than sorted
+type orderedObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func (obj orderedObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, k := range obj.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(obj.values[k])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// orderedJSON returns a simplified item that marshals its attributes in
+// order first, then the rest sorted by name. Nested maps stay sorted.
+func orderedJSON(item map[string]any, order []string) any {
+	if len(order) == 0 {
+		return item
+	}
+	keys := make([]string, 0, len(item))
+	for _, k := range order {
+		if _, ok := item[k]; ok && !slices.Contains(keys, k) {
+			keys = append(keys, k)
+		}
+	}
+	rest := make([]string, 0, len(item)-len(keys))
+	for k := range item {
+		if !slices.Contains(keys, k) {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	return orderedObject{keys: append(keys, rest...), values: item}
+}
+
+// multilineString matches a line of indented JSON holding just a string
+// value, with or without a key: indent, key, value, comma
+var multilineString = regexp.MustCompile(`^(\s*)("(?:[^"\\]|\\.)*": )?("(?:[^"\\]|\\.)*\\n(?:[^"\\]|\\.)*")(,?)$`)
+
+// breakMultilineStrings rewrites string values containing newlines in
+// indented JSON as blocks indented one more level, YAML style:
+//
+//	"notes": |
+//	  first line
+//	  second line
+func breakMultilineStrings(s, indentUnit string) string {
+	lines := strings.Split(s, "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		match := multilineString.FindStringSubmatch(line)
+		var value string
+		if match == nil || json.Unmarshal([]byte(match[3]), &value) != nil {
+			out = append(out, line)
+			continue
+		}
+		indent := match[1]
+		out = append(out, indent+match[2]+"|")
+		for _, text := range strings.Split(strings.TrimSuffix(value, "\n"), "\n") {
+			out = append(out, indent+indentUnit+text)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// viewItem returns the attributes of item the item view shows
+func (o displayOptions) viewItem(item map[string]types.AttributeValue) map[string]types.AttributeValue {
+	if !o.hideEmpty {
+		return item
+	}
+	shown := make(map[string]types.AttributeValue, len(item))
+	for name, av := range item {
+		if !isEmptyValue(av) {
+			shown[name] = av
+		}
+	}
+	return shown
+}
+
+// isEmptyValue reports whether av is null, an empty string, or an empty
+// list or map
+func isEmptyValue(av types.AttributeValue) bool {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberNULL:
+		return true
+	case *types.AttributeValueMemberS:
+		return v.Value == ""
+	case *types.AttributeValueMemberL:
+		return len(v.Value) == 0
+	case *types.AttributeValueMemberM:
+		return len(v.Value) == 0
+	}
+	return false
+}
+
+// groupDigits inserts thousands separators into the integer part of a
+// decimal number, e.g. "-1234567.5" becomes "-1,234,567.5". It reports false
+// for values it doesn't understand (like exponents) or that need no grouping.
+func groupDigits(n string) (string, bool) {
+	sign := ""
+	if strings.HasPrefix(n, "-") || strings.HasPrefix(n, "+") {
+		sign, n = n[:1], n[1:]
+	}
+	intPart, frac, hasFrac := strings.Cut(n, ".")
+	if len(intPart) <= 3 || strings.Trim(intPart, "0123456789") != "" {
+		return "", false
+	}
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	if hasFrac {
+		b.WriteByte('.')
+		b.WriteString(frac)
+	}
+	return b.String(), true
+}
+
+// colorizeJSON colors the values of compact (possibly truncated) JSON by
+// type: strings, numbers, and dimmed booleans and nulls. Keys and
+// punctuation use base, so a highlighted row keeps its background.
+func colorizeJSON(s string, base lipgloss.Style) string {
+	stringStyle := base.Foreground(successColor)
+	numberStyle := base.Foreground(primaryColor)
+	dimStyle := base.Foreground(lipgloss.Color("240"))
+
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '"':
+			// Find the end of the string literal
+			j := i + 1
+			for j < len(s) && s[j] != '"' {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j+1, len(s))
+			if j < len(s) && s[j] == ':' {
+				b.WriteString(base.Render(s[i:j])) // key
+			} else {
+				b.WriteString(stringStyle.Render(s[i:j]))
+			}
+			i = j
+		case c == '-' || (c >= '0' && c <= '9'):
+			j := i + 1
+			for j < len(s) && strings.IndexByte("0123456789.eE+-", s[j]) >= 0 {
+				j++
+			}
+			b.WriteString(numberStyle.Render(s[i:j]))
+			i = j
+		case c == 't' || c == 'f' || c == 'n':
+			j := i + 1
+			for j < len(s) && s[j] >= 'a' && s[j] <= 'z' {
+				j++
+			}
+			b.WriteString(dimStyle.Render(s[i:j]))
+			i = j
+		default:
+			j := i + 1
+			for j < len(s) && strings.IndexByte("\"-0123456789tfn", s[j]) < 0 {
+				j++
+			}
+			b.WriteString(base.Render(s[i:j]))
+			i = j
+		}
+	}
+	return b.String()
+}
+
+// sortKeyColumns is the list layout of a sort key split into segments
+type sortKeyColumns struct {
+	delimiter string
+	labels    []string
+	widths    []int
+}
+
+// maxSortKeySegments caps the columns used when no labels are configured
+const maxSortKeySegments = 6
+
+// sortKeyColumns returns the segment layout for table's sort key sized to
+// fit items, or nil if the table has no composite sort key configured
+func (o displayOptions) sortKeyColumns(table *TableInfo, items []map[string]types.AttributeValue) *sortKeyColumns {
+	format, ok := o.sortKeys[table.Name]
+	if !ok || format.Delimiter == "" || table.SortKey == "" {
+		return nil
+	}
+
+	n := len(format.Labels)
+	if n == 0 {
+		n = 1
+		for _, item := range items {
+			n = max(n, strings.Count(GetKeyValue(item, table.SortKey), format.Delimiter)+1)
+		}
+		n = min(n, maxSortKeySegments)
+	}
+
+	c := &sortKeyColumns{delimiter: format.Delimiter, labels: make([]string, n), widths: make([]int, n)}
+	for i := range n {
+		if i < len(format.Labels) {
+			c.labels[i] = format.Labels[i]
+		} else {
+			c.labels[i] = fmt.Sprintf("%s.%d", table.SortKey, i+1)
+		}
+		c.widths[i] = len(c.labels[i])
+	}
+	for _, item := range items {
+		for i, seg := range c.split(GetKeyValue(item, table.SortKey)) {
+			c.widths[i] = max(c.widths[i], len(seg))
+		}
+	}
+	for i := range c.widths {
+		c.widths[i] = min(c.widths[i], 20)
+	}
+	return c
+}
+
+// split breaks a sort key value into at most len(c.widths) segments
+func (c *sortKeyColumns) split(sk string) []string {
+	return strings.SplitN(sk, c.delimiter, len(c.widths))
+}
+
+// width is the total width of the segment columns and their separators
+func (c *sortKeyColumns) width() int {
+	w := 3 * (len(c.widths) - 1)
+	for _, sw := range c.widths {
+		w += sw
+	}
+	return w
+}
+
+// row renders values (segments or labels) padded into the columns
+func (c *sortKeyColumns) row(values []string) string {
+	cells := make([]string, len(c.widths))
+	for i, w := range c.widths {
+		v := ""
+		if i < len(values) {
+			v = values[i]
+		}
+		cells[i] = fmt.Sprintf("%-*s", w, truncate(v, w))
+	}
+	return strings.Join(cells, " │ ")
+}
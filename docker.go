@@ -0,0 +1,111 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"strings"
+)
+
+// dockerScheme is the endpoint prefix that asks for the endpoint of a local
+// DynamoDB running in Docker: docker:// for any, docker://name for one
+const dockerScheme = "docker://"
+
+// dockerImage is the image of DynamoDB Local
+const dockerImage = "amazon/dynamodb-local"
+
+// dockerLabel marks other containers as a DynamoDB to connect to
+const dockerLabel = "dui.dynamodb"
+
+// dockerPort is the port DynamoDB Local listens on inside its container
+const dockerPort = "8000/tcp"
+
+// dockerResolvedMsg carries the endpoint a docker:// endpoint switch found,
+// to switch to
+type dockerResolvedMsg struct {
+	endpoint string
+	region   string
+	err      error
+}
+
+// dockerEndpoint finds the endpoint of a running DynamoDB Local container:
+// the one named name, or else the only one running the DynamoDB Local image
+// or labeled dui.dynamodb. It asks the docker CLI for the host port mapped
+// to the container's port 8000.
+func dockerEndpoint(ctx context.Context, name string) (string, error) {
+	out, err := runDocker(ctx, "ps", "--format", "{{.ID}}\t{{.Names}}\t{{.Image}}\t{{.Labels}}")
+	if err != nil {
+		return "", err
+	}
+
+	var ids, names []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 4 {
+			continue
+		}
+		id, cname, image, labels := fields[0], fields[1], fields[2], fields[3]
+		var match bool
+		if name != "" {
+			match = cname == name || strings.HasPrefix(id, name)
+		} else {
+			match = image == dockerImage || strings.HasPrefix(image, dockerImage+":") ||
+				strings.Contains(","+labels, ","+dockerLabel+"=")
+		}
+		if match {
+			ids = append(ids, id)
+			names = append(names, cname)
+		}
+	}
+	switch {
+	case len(ids) == 0 && name != "":
+		return "", fmt.Errorf("no running container named %s", name)
+	case len(ids) == 0:
+		return "", fmt.Errorf("no running %s container (start one, or pass -e)", dockerImage)
+	case len(ids) > 1:
+		return "", fmt.Errorf("several DynamoDB containers are running (%s): pick one with -e docker://name", strings.Join(names, ", "))
+	}
+
+	out, err = runDocker(ctx, "port", ids[0], dockerPort)
+	if err != nil {
+		return "", fmt.Errorf("container %s doesn't publish port %s: %w", names[0], dockerPort, err)
+	}
+	// One line per address family, e.g. 0.0.0.0:32768 and [::]:32768
+	host, port, err := net.SplitHostPort(strings.TrimSpace(strings.SplitN(out, "\n", 2)[0]))
+	if err != nil {
+		return "", fmt.Errorf("unexpected docker port output %q", strings.TrimSpace(out))
+	}
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, port), nil
+}
+
+// runDocker runs the docker CLI and returns its output, turning the usual
+// failures into messages saying what to do
+func runDocker(ctx context.Context, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		return "", fmt.Errorf("docker isn't installed or not in PATH; pass -e with the endpoint instead")
+	case errors.As(err, &exitErr):
+		msg := strings.TrimSpace(stderr.String())
+		if strings.Contains(msg, "Cannot connect to the Docker daemon") {
+			return "", fmt.Errorf("docker isn't running; start it or pass -e with the endpoint")
+		}
+		return "", fmt.Errorf("docker %s: %s", args[0], msg)
+	case err != nil:
+		return "", fmt.Errorf("docker %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
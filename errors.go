@@ -0,0 +1,62 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"errors"
+
+	"github.com/aws/smithy-go"
+)
+
+// errorKind is a category of failure the UI reacts to differently
+type errorKind int
+
+const (
+	errOther      errorKind = iota
+	errNotFound             // table or index doesn't exist
+	errThrottled            // request rate or throughput exceeded
+	errValidation           // malformed request
+	errPermission           // credentials lack access or are invalid
+	errConflict             // condition check failed
+)
+
+// errorCodes maps DynamoDB error codes to their kind
+var errorCodes = map[string]errorKind{
+	"ResourceNotFoundException":              errNotFound,
+	"ProvisionedThroughputExceededException": errThrottled,
+	"ThrottlingException":                    errThrottled,
+	"RequestLimitExceeded":                   errThrottled,
+	"ValidationException":                    errValidation,
+	"AccessDeniedException":                  errPermission,
+	"UnrecognizedClientException":            errPermission,
+	"MissingAuthenticationToken":             errPermission,
+	"ExpiredTokenException":                  errPermission,
+	"ConditionalCheckFailedException":        errConflict,
+	"TransactionCanceledException":           errConflict,
+}
+
+// classifyError returns the kind of a DynamoDB error
+func classifyError(err error) errorKind {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return errorCodes[apiErr.ErrorCode()]
+	}
+	return errOther
+}
+
+// hint suggests what to do about an error of this kind, or "" if there's
+// nothing to add
+func (k errorKind) hint() string {
+	switch k {
+	case errNotFound:
+		return "Not found: the table or index doesn't exist at this endpoint and region"
+	case errThrottled:
+		return "Throttled: still over capacity after retrying, try again shortly"
+	case errPermission:
+		return "Access denied: check the IAM permissions and credentials in use"
+	case errConflict:
+		return "Condition failed: the item changed or didn't match"
+	}
+	return ""
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// executeExport writes the selected or loaded items to a file. The format
+// defaults to json for .json files and jsonl otherwise.
+func (m *Model) executeExport(args []string) tea.Cmd {
+	if len(args) < 1 || len(args) > 2 {
+		m.status = "Usage: /export file [jsonl|json|dynamodb-jsonl|dynamodb-json]"
+		return nil
+	}
+	path := args[0]
+	format := outputJSONL
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		format = outputJSON
+	}
+	if len(args) == 2 {
+		var err error
+		if format, err = parseOutputFormat(args[1]); err != nil {
+			m.setError(err)
+			return nil
+		}
+	}
+	return m.exportFile(path, func(w io.Writer, items []map[string]types.AttributeValue) error {
+		return writeItems(w, items, format)
+	})
+}
+
+// executeCSV writes the selected or loaded items to a CSV file
+func (m *Model) executeCSV(args []string) tea.Cmd {
+	if len(args) != 1 {
+		m.status = "Usage: /csv file"
+		return nil
+	}
+	var keys []string
+	if len(m.tables) > 0 {
+		table := m.tables[m.currentTable]
+		keys = append(keys, table.PartitionKey)
+		if table.SortKey != "" {
+			keys = append(keys, table.SortKey)
+		}
+	}
+	return m.exportFile(args[0], func(w io.Writer, items []map[string]types.AttributeValue) error {
+		return writeCSV(w, items, keys)
+	})
+}
+
+// exportFile creates path and writes the export items to it with write
+func (m *Model) exportFile(path string, write func(io.Writer, []map[string]types.AttributeValue) error) tea.Cmd {
+	items := m.targetItems()
+	if len(items) == 0 {
+		m.status = "No items to export"
+		return nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		m.setError(err)
+		return nil
+	}
+	err = write(f, items)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		m.setError(fmt.Errorf("export failed: %w", err))
+		return nil
+	}
+	what := "items"
+	if len(m.selected) > 0 {
+		what = "selected items"
+	}
+	m.status = fmt.Sprintf("Exported %d %s to %s", len(items), what, path)
+	return nil
+}
+
+// writeCSV writes items as CSV with a header row. The key attributes come
+// first, then every other attribute in name order. Strings and numbers are
+// written as is, other values as JSON.
+func writeCSV(w io.Writer, items []map[string]types.AttributeValue, keys []string) error {
+	columns := append([]string(nil), keys...)
+	seen := make(map[string]bool)
+	for _, k := range keys {
+		seen[k] = true
+	}
+	var rest []string
+	for _, item := range items {
+		for name := range item {
+			if !seen[name] {
+				seen[name] = true
+				rest = append(rest, name)
+			}
+		}
+	}
+	sort.Strings(rest)
+	columns = append(columns, rest...)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	row := make([]string, len(columns))
+	for _, item := range items {
+		for i, name := range columns {
+			row[i] = csvValue(item[name])
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvValue formats one attribute value as a CSV field
+func csvValue(av types.AttributeValue) string {
+	switch v := av.(type) {
+	case nil:
+		return ""
+	case *types.AttributeValueMemberS:
+		return v.Value
+	case *types.AttributeValueMemberN:
+		return v.Value
+	}
+	data, _ := json.Marshal(attrToInterface(av))
+	return string(data)
+}
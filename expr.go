@@ -0,0 +1,368 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// exprBuilder allocates expression placeholders: #a0, #a1, ... for attribute
+// names and :v0, :v1, ... for values. Aliasing every attribute name avoids
+// collisions with DynamoDB reserved words like status or name.
+type exprBuilder struct {
+	names  map[string]string
+	values map[string]types.AttributeValue
+	// aliases maps an attribute name to its placeholder so it's reused
+	aliases map[string]string
+}
+
+// exprBuilder returns a builder that continues the placeholders already in
+// op, so every expression of an operation shares one set of aliases. Store
+// its names and values back into op when done.
+func (op *operation) exprBuilder() *exprBuilder {
+	e := &exprBuilder{names: op.names, values: op.values, aliases: make(map[string]string)}
+	for alias, attr := range op.names {
+		e.aliases[attr] = alias
+	}
+	return e
+}
+
+// name returns the placeholder for an attribute name
+func (e *exprBuilder) name(attr string) string {
+	if alias, ok := e.aliases[attr]; ok {
+		return alias
+	}
+	if e.names == nil {
+		e.names = make(map[string]string)
+		e.aliases = make(map[string]string)
+	}
+	alias := fmt.Sprintf("#a%d", len(e.names))
+	e.names[alias] = attr
+	e.aliases[attr] = alias
+	return alias
+}
+
+// path returns the document path of an attribute path such as
+// items[0].price, with every name replaced by a placeholder: #a0[0].#a1.
+// Paths that don't parse are taken as a single attribute name.
+func (e *exprBuilder) path(attr string) string {
+	parts, ok := splitPath(attr)
+	if !ok {
+		return e.name(attr)
+	}
+	segments := make([]string, len(parts))
+	for i, part := range parts {
+		if part.name == "" {
+			return e.name(attr)
+		}
+		segments[i] = e.name(part.name)
+		for _, n := range part.indexes {
+			segments[i] += fmt.Sprintf("[%d]", n)
+		}
+	}
+	return strings.Join(segments, ".")
+}
+
+// value returns a new placeholder for a value
+func (e *exprBuilder) value(av types.AttributeValue) string {
+	if e.values == nil {
+		e.values = make(map[string]types.AttributeValue)
+	}
+	placeholder := fmt.Sprintf(":v%d", len(e.values))
+	e.values[placeholder] = av
+	return placeholder
+}
+
+// condition returns "attr op value" with both sides replaced by placeholders
+func (e *exprBuilder) condition(attr, op string, av types.AttributeValue) string {
+	return fmt.Sprintf("%s %s %s", e.name(attr), op, e.value(av))
+}
+
+// unchangedCondition returns a condition that holds while the stored item
+// still matches expected, or that no item exists when expected is nil.
+// Items too large to compare fall back to checking that the item exists.
+func unchangedCondition(e *exprBuilder, table *TableInfo, expected map[string]types.AttributeValue) string {
+	if expected == nil {
+		return "attribute_not_exists(" + e.name(table.PartitionKey) + ")"
+	}
+	attrs := make([]string, 0, len(expected))
+	for attr := range expected {
+		attrs = append(attrs, attr)
+	}
+	sort.Strings(attrs)
+
+	var b exprBuilder
+	parts := make([]string, 0, len(attrs))
+	for _, attr := range attrs {
+		if _, ok := expected[attr].(*types.AttributeValueMemberNULL); ok {
+			parts = append(parts, "attribute_exists("+b.name(attr)+")")
+			continue
+		}
+		parts = append(parts, b.condition(attr, "=", expected[attr]))
+	}
+	condition := strings.Join(parts, " AND ")
+	if len(condition) > maxConditionLength {
+		return "attribute_exists(" + e.name(table.PartitionKey) + ")"
+	}
+	*e = b
+	return condition
+}
+
+// parseCondition splits an attr<op>value argument such as "ts>=2024" into
+// its parts. Supported operators are =, <, <=, >, and >=.
+func parseCondition(arg string) (attr, op string, av types.AttributeValue, err error) {
+	idx := strings.IndexAny(arg, "=<>")
+	if idx <= 0 {
+		return "", "", nil, fmt.Errorf("invalid condition: %s (expected attr=value, attr<value, ...)", arg)
+	}
+	attr = strings.TrimSpace(arg[:idx])
+	op = arg[idx : idx+1]
+	rest := arg[idx+1:]
+	if op != "=" && strings.HasPrefix(rest, "=") {
+		op += "="
+		rest = rest[1:]
+	}
+	return attr, op, inferAttributeValue(strings.TrimSpace(rest)), nil
+}
+
+// parseQuery parses /query arguments, [indexName] pk=value [sk<op>value],
+// into a query operation on table
+func parseQuery(table string, args []string) (*operation, error) {
+	indexName := ""
+	keyArgs := args
+
+	// Check if first arg is an index name
+	if len(args) > 1 && !strings.ContainsAny(args[0], "=<>") {
+		indexName = args[0]
+		keyArgs = args[1:]
+	}
+
+	if len(keyArgs) == 0 || len(keyArgs) > 2 {
+		return nil, fmt.Errorf("usage: [indexName] pk=value [sk<op>value]")
+	}
+
+	// Parse the partition key condition
+	pkName, pkValue, err := ParseKeyValue(keyArgs[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var eb exprBuilder
+	keyCondition := eb.condition(pkName, "=", pkValue)
+
+	// Optional sort key condition: sk=v, sk<v, sk<=v, sk>v, sk>=v
+	skAttr := ""
+	if len(keyArgs) == 2 {
+		skName, op, skValue, err := parseCondition(keyArgs[1])
+		if err != nil {
+			return nil, err
+		}
+		keyCondition += " AND " + eb.condition(skName, op, skValue)
+		skAttr = skName
+	}
+
+	return &operation{
+		kind:         "query",
+		table:        table,
+		index:        indexName,
+		keyCondition: keyCondition,
+		names:        eb.names,
+		values:       eb.values,
+		pkAttr:       pkName,
+		skAttr:       skAttr,
+		pkValue:      pkValue,
+	}, nil
+}
+
+// validateQueryKeys checks that a query's key attributes match the key
+// schema of the table or index it targets, suggesting an index that fits
+// when they don't
+func validateQueryKeys(table *TableInfo, op *operation) error {
+	target := "table " + table.Name
+	pk, sk := table.PartitionKey, table.SortKey
+	if op.index != "" {
+		idx := table.index(op.index)
+		if idx == nil {
+			return fmt.Errorf("table %s has no index named '%s'", table.Name, op.index)
+		}
+		target = "index " + idx.Name
+		pk, sk = idx.PartitionKey, idx.SortKey
+	}
+
+	if op.pkAttr != pk {
+		msg := fmt.Sprintf("'%s' is not the partition key of %s (it's '%s')", op.pkAttr, target, pk)
+		if op.index != "" && table.PartitionKey == op.pkAttr {
+			msg += fmt.Sprintf("; try /query %s=...", op.pkAttr)
+		}
+		for _, idx := range table.indexes() {
+			if idx.PartitionKey == op.pkAttr && idx.Name != op.index {
+				msg += fmt.Sprintf("; try /query %s %s=...", idx.Name, op.pkAttr)
+				break
+			}
+		}
+		return fmt.Errorf("%s", msg)
+	}
+	if op.skAttr != "" && op.skAttr != sk {
+		if sk == "" {
+			return fmt.Errorf("%s has no sort key, so '%s' can't be used in the key condition", target, op.skAttr)
+		}
+		return fmt.Errorf("'%s' is not the sort key of %s (it's '%s')", op.skAttr, target, sk)
+	}
+	return nil
+}
+
+// parseCount parses /count arguments: optional query arguments (see
+// parseQuery) plus filter:attr<op>value conditions. Without query arguments
+// the count is a scan.
+func parseCount(table string, args []string) (*operation, error) {
+	var queryArgs, filters []string
+	for _, arg := range args {
+		if cond, ok := strings.CutPrefix(arg, "filter:"); ok {
+			filters = append(filters, cond)
+		} else {
+			queryArgs = append(queryArgs, arg)
+		}
+	}
+
+	op := &operation{kind: "scan", table: table, count: true}
+	if len(queryArgs) == 1 && !strings.ContainsAny(queryArgs[0], "=<>") {
+		// Just an index name
+		op.index = queryArgs[0]
+	} else if len(queryArgs) > 0 {
+		var err error
+		if op, err = parseQuery(table, queryArgs); err != nil {
+			return nil, err
+		}
+		op.count = true
+	}
+
+	// Continue numbering placeholders after the key condition's
+	eb := op.exprBuilder()
+	var conditions []string
+	for _, f := range filters {
+		attr, cmp, av, err := parseCondition(f)
+		if err != nil {
+			return nil, err
+		}
+		// Filters can reach into maps and lists, like filter:items[0].price>10
+		conditions = append(conditions, fmt.Sprintf("%s %s %s", eb.path(attr), cmp, eb.value(av)))
+	}
+	op.filter = strings.Join(conditions, " AND ")
+	op.names, op.values = eb.names, eb.values
+	return op, nil
+}
+
+// sortKeyQuery narrows a query to a sort key condition within the partition
+// it queried: /sk <op> value, where op is =, <, <=, >, >=, begins_with, or
+// between (which takes two values). sample is an existing sort key value
+// whose type the new values take, or nil to infer it.
+func sortKeyQuery(last *operation, skAttr string, sample types.AttributeValue, args []string) (*operation, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("usage: /sk <op> value (op: = < <= > >= begins_with between)")
+	}
+	var e exprBuilder
+	keyCondition := e.condition(last.pkAttr, "=", last.pkValue) + " AND "
+	op := strings.ToLower(args[0])
+	switch op {
+	case "=", "<", "<=", ">", ">=":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("usage: /sk %s value", op)
+		}
+		keyCondition += e.condition(skAttr, op, keyValueLike(sample, args[1]))
+	case "begins_with":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("usage: /sk begins_with prefix")
+		}
+		keyCondition += fmt.Sprintf("begins_with(%s, %s)", e.name(skAttr), e.value(keyValueLike(sample, args[1])))
+	case "between":
+		if len(args) != 3 {
+			return nil, fmt.Errorf("usage: /sk between low high")
+		}
+		keyCondition += fmt.Sprintf("%s BETWEEN %s AND %s", e.name(skAttr),
+			e.value(keyValueLike(sample, args[1])), e.value(keyValueLike(sample, args[2])))
+	default:
+		return nil, fmt.Errorf("unknown sort key operator %q (use = < <= > >= begins_with between)", args[0])
+	}
+
+	return &operation{
+		kind:         "query",
+		table:        last.table,
+		index:        last.index,
+		keyCondition: keyCondition,
+		names:        e.names,
+		values:       e.values,
+		pkAttr:       last.pkAttr,
+		skAttr:       skAttr,
+		pkValue:      last.pkValue,
+	}, nil
+}
+
+// keyValueLike returns s with the type of sample (S, N, or B), inferring the
+// type when there's no sample
+func keyValueLike(sample types.AttributeValue, s string) types.AttributeValue {
+	switch sample.(type) {
+	case *types.AttributeValueMemberS:
+		return &types.AttributeValueMemberS{Value: s}
+	case *types.AttributeValueMemberN:
+		return &types.AttributeValueMemberN{Value: s}
+	case *types.AttributeValueMemberB:
+		return &types.AttributeValueMemberB{Value: []byte(s)}
+	default:
+		return inferAttributeValue(s)
+	}
+}
+
+// keysOnlyScan returns a scan of table that reads only the key attributes
+func keysOnlyScan(table *TableInfo, maxItems int) *operation {
+	var e exprBuilder
+	projection := e.name(table.PartitionKey)
+	if table.SortKey != "" {
+		projection += ", " + e.name(table.SortKey)
+	}
+	return &operation{kind: "scan", table: table.Name, projection: projection, names: e.names, maxItems: maxItems}
+}
+
+// readsWholeTable reports whether the operation reads every item of the
+// base table, so finding none means the table is empty
+func (op *operation) readsWholeTable() bool {
+	return op.kind == "scan" && op.index == "" && op.filter == "" && !op.count
+}
+
+// describe summarizes the operation for the header, e.g. "scan" or
+// "query index:email email = a@b.c", with placeholders resolved
+func (op *operation) describe() string {
+	desc := op.kind
+	if op.index != "" {
+		desc += " index:" + op.index
+	}
+	if op.projection != "" {
+		desc += " keys"
+	}
+	if op.keyCondition == "" {
+		return desc
+	}
+
+	// Replace longer placeholders first so #a1 doesn't match inside #a10
+	var placeholders []string
+	for p := range op.names {
+		placeholders = append(placeholders, p)
+	}
+	for p := range op.values {
+		placeholders = append(placeholders, p)
+	}
+	sort.Slice(placeholders, func(i, j int) bool { return len(placeholders[i]) > len(placeholders[j]) })
+	var pairs []string
+	for _, p := range placeholders {
+		if name, ok := op.names[p]; ok {
+			pairs = append(pairs, p, name)
+		} else {
+			pairs = append(pairs, p, filterString(op.values[p]))
+		}
+	}
+	return desc + " " + strings.NewReplacer(pairs...).Replace(op.keyCondition)
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// executeFocus shows only the subtree at path of the current item in the
+// item view, like meta.address or items[0]. Without a path it shows the
+// whole item again.
+func (m *Model) executeFocus(args []string) tea.Cmd {
+	item := m.getCurrentItem()
+	if item == nil {
+		m.status = "No item selected"
+		return nil
+	}
+	if len(args) > 1 {
+		m.status = "Usage: :focus [path], e.g. :focus meta.address"
+		return nil
+	}
+	m.mode = ModeItemView
+	if len(args) == 0 {
+		m.focusPath = ""
+		m.refreshItemView()
+		return nil
+	}
+	if _, ok := attrAtPath(item, args[0]); !ok {
+		m.status = "No attribute at " + args[0]
+		m.refreshItemView()
+		return nil
+	}
+	m.focusPath = args[0]
+	m.refreshItemView()
+	m.status = "Focused on " + args[0] + " (Backspace for the whole item)"
+	return nil
+}
+
+// itemViewContent renders item for the item view: the whole item, less
+// any hidden empty attributes, or the focused subtree under its path
+func (m *Model) itemViewContent(item map[string]types.AttributeValue) string {
+	if m.focusPath != "" {
+		if av, ok := attrAtPath(item, m.focusPath); ok {
+			return m.display.prettyJSON(map[string]types.AttributeValue{m.focusPath: av})
+		}
+	}
+	return m.display.prettyJSON(m.display.viewItem(item))
+}
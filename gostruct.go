@@ -0,0 +1,291 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// goInitialisms are name parts written in all caps, as Go style has it
+var goInitialisms = map[string]bool{
+	"API": true, "ARN": true, "HTTP": true, "ID": true, "JSON": true,
+	"TTL": true, "URL": true, "UUID": true,
+}
+
+// goStructGen infers Go struct definitions from items. Nested maps become
+// their own structs, named after the attribute holding them.
+type goStructGen struct {
+	decls []string
+	types map[string]bool
+}
+
+// itemGoCode returns a Go struct definition for item, with any nested
+// structs, and a literal of it populated with item's values. The types are
+// a best-effort inference from this one item.
+func itemGoCode(item map[string]types.AttributeValue) string {
+	g := &goStructGen{types: make(map[string]bool)}
+	name := g.structType("Item", []map[string]types.AttributeValue{item})
+	src := strings.Join(g.decls, "\n\n") + "\n\nvar item = " + g.literal(&types.AttributeValueMemberM{Value: item}, name) + "\n"
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return src
+	}
+	return string(formatted)
+}
+
+// structType declares a struct with the attributes of maps, which share one
+// type like the elements of a list, and returns its name
+func (g *goStructGen) structType(name string, maps []map[string]types.AttributeValue) string {
+	for base, i := name, 2; g.types[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	g.types[name] = true
+	// Reserve the declaration's place so it comes before nested structs
+	slot := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	// The first value of each attribute decides its type
+	values := make(map[string]types.AttributeValue)
+	for _, m := range maps {
+		for attr, av := range m {
+			if _, ok := values[attr]; !ok {
+				values[attr] = av
+			}
+		}
+	}
+	attrs := make([]string, 0, len(values))
+	for attr := range values {
+		attrs = append(attrs, attr)
+	}
+	sort.Strings(attrs)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "type %s struct {\n", name)
+	fields := make(map[string]bool)
+	for _, attr := range attrs {
+		field := goFieldName(attr, fields)
+		var nested []map[string]types.AttributeValue
+		for _, m := range maps {
+			if v, ok := m[attr].(*types.AttributeValueMemberM); ok {
+				nested = append(nested, v.Value)
+			}
+		}
+		fmt.Fprintf(&b, "\t%s %s `dynamodbav:%q`\n", field, g.goType(field, values[attr], nested), attr)
+	}
+	b.WriteString("}")
+	g.decls[slot] = b.String()
+	return name
+}
+
+// goType returns the Go type of av. nested are all the maps seen for the
+// attribute, so a struct covers the attributes of every one of them.
+func (g *goStructGen) goType(field string, av types.AttributeValue, nested []map[string]types.AttributeValue) string {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		return "string"
+	case *types.AttributeValueMemberN:
+		return goNumberType(v.Value)
+	case *types.AttributeValueMemberBOOL:
+		return "bool"
+	case *types.AttributeValueMemberB:
+		return "[]byte"
+	case *types.AttributeValueMemberSS:
+		return "[]string"
+	case *types.AttributeValueMemberNS:
+		elem := "int64"
+		for _, n := range v.Value {
+			if goNumberType(n) != "int64" {
+				elem = "float64"
+			}
+		}
+		return "[]" + elem
+	case *types.AttributeValueMemberBS:
+		return "[][]byte"
+	case *types.AttributeValueMemberM:
+		return g.structType(field, nested)
+	case *types.AttributeValueMemberL:
+		return g.listType(field, v.Value)
+	}
+	return "any"
+}
+
+// listType returns a typed slice when every element has the same scalar
+// type or is a map, and []any otherwise
+func (g *goStructGen) listType(field string, elems []types.AttributeValue) string {
+	if len(elems) == 0 {
+		return "[]any"
+	}
+	var maps []map[string]types.AttributeValue
+	for _, elem := range elems {
+		if v, ok := elem.(*types.AttributeValueMemberM); ok {
+			maps = append(maps, v.Value)
+		}
+	}
+	if len(maps) == len(elems) {
+		return "[]" + g.structType(strings.TrimSuffix(field, "s"), maps)
+	}
+	if len(maps) > 0 {
+		return "[]any"
+	}
+
+	elem := g.goType(field, elems[0], nil)
+	for _, e := range elems[1:] {
+		t := g.goType(field, e, nil)
+		if t == "float64" && elem == "int64" || t == "int64" && elem == "float64" {
+			elem = "float64"
+		} else if t != elem {
+			return "[]any"
+		}
+	}
+	if strings.HasPrefix(elem, "[]") {
+		// Lists of lists are rarely uniform enough to be worth typing
+		return "[]any"
+	}
+	return "[]" + elem
+}
+
+// literal returns a Go literal of av as type typ
+func (g *goStructGen) literal(av types.AttributeValue, typ string) string {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberM:
+		if typ == "any" || !g.types[typ] {
+			return anyLiteral(av)
+		}
+		attrs := make([]string, 0, len(v.Value))
+		for attr := range v.Value {
+			attrs = append(attrs, attr)
+		}
+		sort.Strings(attrs)
+		// Field types come from the struct declaration
+		fieldTypes := g.fieldTypes(typ)
+		var b strings.Builder
+		b.WriteString(typ + "{\n")
+		fields := make(map[string]bool)
+		for _, attr := range attrs {
+			field := goFieldName(attr, fields)
+			fmt.Fprintf(&b, "%s: %s,\n", field, g.literal(v.Value[attr], fieldTypes[field]))
+		}
+		b.WriteString("}")
+		return b.String()
+	case *types.AttributeValueMemberL:
+		if !strings.HasPrefix(typ, "[]") {
+			return anyLiteral(av)
+		}
+		elemType := strings.TrimPrefix(typ, "[]")
+		parts := make([]string, len(v.Value))
+		for i, elem := range v.Value {
+			parts[i] = g.literal(elem, elemType)
+		}
+		return typ + "{" + strings.Join(parts, ", ") + "}"
+	case *types.AttributeValueMemberNS:
+		if strings.HasPrefix(typ, "[]") {
+			return typ + "{" + strings.Join(v.Value, ", ") + "}"
+		}
+	}
+	return anyLiteral(av)
+}
+
+// fieldTypes returns the field types of a declared struct by field name
+func (g *goStructGen) fieldTypes(name string) map[string]string {
+	fieldTypes := make(map[string]string)
+	for _, decl := range g.decls {
+		if !strings.HasPrefix(decl, "type "+name+" struct {") {
+			continue
+		}
+		for _, line := range strings.Split(decl, "\n")[1:] {
+			if parts := strings.Fields(line); len(parts) >= 2 {
+				fieldTypes[parts[0]] = parts[1]
+			}
+		}
+	}
+	return fieldTypes
+}
+
+// anyLiteral returns a Go literal of av where no struct type applies
+func anyLiteral(av types.AttributeValue) string {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		return strconv.Quote(v.Value)
+	case *types.AttributeValueMemberN:
+		return v.Value
+	case *types.AttributeValueMemberBOOL:
+		return strconv.FormatBool(v.Value)
+	case *types.AttributeValueMemberB:
+		return fmt.Sprintf("[]byte(%q)", v.Value)
+	case *types.AttributeValueMemberSS:
+		parts := make([]string, len(v.Value))
+		for i, s := range v.Value {
+			parts[i] = strconv.Quote(s)
+		}
+		return "[]string{" + strings.Join(parts, ", ") + "}"
+	case *types.AttributeValueMemberNS:
+		return "[]float64{" + strings.Join(v.Value, ", ") + "}"
+	case *types.AttributeValueMemberBS:
+		parts := make([]string, len(v.Value))
+		for i, b := range v.Value {
+			parts[i] = fmt.Sprintf("%q", b)
+		}
+		return "[][]byte{" + strings.Join(parts, ", ") + "}"
+	case *types.AttributeValueMemberL:
+		parts := make([]string, len(v.Value))
+		for i, elem := range v.Value {
+			parts[i] = anyLiteral(elem)
+		}
+		return "[]any{" + strings.Join(parts, ", ") + "}"
+	case *types.AttributeValueMemberM:
+		attrs := make([]string, 0, len(v.Value))
+		for attr := range v.Value {
+			attrs = append(attrs, attr)
+		}
+		sort.Strings(attrs)
+		parts := make([]string, len(attrs))
+		for i, attr := range attrs {
+			parts[i] = strconv.Quote(attr) + ": " + anyLiteral(v.Value[attr])
+		}
+		return "map[string]any{" + strings.Join(parts, ", ") + "}"
+	}
+	return "nil"
+}
+
+// goNumberType is int64 for whole numbers and float64 otherwise
+func goNumberType(n string) string {
+	if _, err := strconv.ParseInt(n, 10, 64); err == nil {
+		return "int64"
+	}
+	return "float64"
+}
+
+// goFieldName turns an attribute name like "user_id" into an exported Go
+// identifier like "UserID", unique among fields
+func goFieldName(attr string, fields map[string]bool) string {
+	parts := strings.FieldsFunc(attr, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, part := range parts {
+		if upper := strings.ToUpper(part); goInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	name := b.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "F" + name
+	}
+	for base, i := name, 2; fields[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	fields[name] = true
+	return name
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import "fmt"
+
+// groupRow is one line of the grouped list: a partition header, or an item
+// of an expanded partition
+type groupRow struct {
+	pk     string
+	header bool
+	count  int // items in the partition, for headers
+	idx    int // item index, or the partition's first item for headers
+}
+
+// groupRows groups the filtered items by partition key value, partitions in
+// order of their first item, and lists the items of expanded partitions
+// under their header
+func (m *Model) groupRows() []groupRow {
+	if len(m.tables) == 0 {
+		return nil
+	}
+	table := m.tables[m.currentTable]
+	var order []string
+	members := make(map[string][]int)
+	for i, item := range m.getFilteredItems() {
+		pk := GetKeyValue(item, table.PartitionKey)
+		if _, ok := members[pk]; !ok {
+			order = append(order, pk)
+		}
+		members[pk] = append(members[pk], i)
+	}
+
+	var rows []groupRow
+	for _, pk := range order {
+		idxs := members[pk]
+		rows = append(rows, groupRow{pk: pk, header: true, count: len(idxs), idx: idxs[0]})
+		if m.collapsed[pk] {
+			continue
+		}
+		for _, idx := range idxs {
+			rows = append(rows, groupRow{pk: pk, idx: idx})
+		}
+	}
+	return rows
+}
+
+// groupCursorRow returns the position of the cursor in rows. The cursor is
+// on the header of its item's partition when onHeader is set or the
+// partition is collapsed.
+func (m *Model) groupCursorRow(rows []groupRow) int {
+	pk := m.cursorPartition()
+	header := 0
+	for i, row := range rows {
+		switch {
+		case row.header && row.pk == pk:
+			header = i
+		case !row.header && !m.onHeader && row.idx == m.cursor:
+			return i
+		}
+	}
+	return header
+}
+
+// cursorPartition returns the partition key value of the cursor item
+func (m *Model) cursorPartition() string {
+	items := m.getFilteredItems()
+	if m.cursor < 0 || m.cursor >= len(items) || len(m.tables) == 0 {
+		return ""
+	}
+	return GetKeyValue(items[m.cursor], m.tables[m.currentTable].PartitionKey)
+}
+
+// setGroupRow moves the cursor to rows[pos], clamped to the list
+func (m *Model) setGroupRow(rows []groupRow, pos int) {
+	if len(rows) == 0 {
+		return
+	}
+	row := rows[max(min(pos, len(rows)-1), 0)]
+	m.cursor = row.idx
+	m.onHeader = row.header
+}
+
+// toggleGrouping switches between the flat list and the list grouped by
+// partition key
+func (m *Model) toggleGrouping() {
+	m.grouped = !m.grouped
+	m.onHeader = false
+	if !m.grouped {
+		m.status = "Grouping off"
+		return
+	}
+	if m.collapsed == nil {
+		m.collapsed = make(map[string]bool)
+	}
+	groups := 0
+	for _, row := range m.groupRows() {
+		if row.header {
+			groups++
+		}
+	}
+	m.status = fmt.Sprintf("Grouped by partition: %d partitions", groups)
+}
+
+// toggleGroup collapses or expands the partition of the cursor. Collapsing
+// leaves the cursor on the partition's header.
+func (m *Model) toggleGroup() {
+	if len(m.getFilteredItems()) == 0 {
+		return
+	}
+	pk := m.cursorPartition()
+	m.collapsed[pk] = !m.collapsed[pk]
+	m.onHeader = true
+}
+
+// toggleAllGroups collapses every partition, or expands them all when they
+// are all collapsed already
+func (m *Model) toggleAllGroups() {
+	rows := m.groupRows()
+	collapse := false
+	for _, row := range rows {
+		if row.header && !m.collapsed[row.pk] {
+			collapse = true
+		}
+	}
+	for _, row := range rows {
+		if row.header {
+			m.collapsed[row.pk] = collapse
+		}
+	}
+	m.onHeader = true
+}
+
+// selectGroup toggles the selection of every item of the cursor's
+// partition: it selects them all unless they are all selected already
+func (m *Model) selectGroup() {
+	pk := m.cursorPartition()
+	table := m.tables[m.currentTable]
+	var idxs []int
+	all := true
+	for i, item := range m.getFilteredItems() {
+		if GetKeyValue(item, table.PartitionKey) == pk {
+			idxs = append(idxs, i)
+			all = all && m.selected[i]
+		}
+	}
+	for _, i := range idxs {
+		if all {
+			delete(m.selected, i)
+		} else {
+			m.selected[i] = true
+		}
+	}
+}
+
+// renderGroupHeader renders the header line of a partition
+func (m *Model) renderGroupHeader(row groupRow, cursor bool, width int) string {
+	marker := "▾ "
+	if m.collapsed[row.pk] {
+		marker = "▸ "
+	}
+	count := "1 item"
+	if row.count != 1 {
+		count = fmt.Sprintf("%d items", row.count)
+	}
+	text := truncate(fmt.Sprintf("%s%s (%s)", marker, row.pk, count), max(width-4, 10))
+	if cursor {
+		return cursorStyle.Render("▶ ") + selectedRowStyle.Bold(true).Render(text)
+	}
+	return "  " + tableRowStyle.Bold(true).Render(text)
+}
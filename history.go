@@ -0,0 +1,121 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultHistorySize caps the history when history_size isn't configured
+const defaultHistorySize = 50
+
+// historyEntry is a place visited: an item opened in the item view, or a
+// partition drilled into
+type historyEntry struct {
+	table string
+	key   map[string]types.AttributeValue // item key, nil for a partition
+	op    *operation                      // partition query
+	label string
+}
+
+// recordItem adds item, about to be shown in the item view, to the history
+func (m *Model) recordItem(item map[string]types.AttributeValue) {
+	table := m.tables[m.currentTable]
+	label := GetKeyValue(item, table.PartitionKey)
+	if table.SortKey != "" {
+		label += " " + GetKeyValue(item, table.SortKey)
+	}
+	m.record(historyEntry{table: table.Name, key: itemPrimaryKey(table, item), label: label})
+}
+
+// record appends e to the history, unless it repeats the last entry, and
+// drops the oldest entries past the configured size. Stepping back starts
+// over from the end.
+func (m *Model) record(e historyEntry) {
+	if n := len(m.history); n > 0 && m.history[n-1].table == e.table && m.history[n-1].label == e.label {
+		m.historyPos = n - 1
+		return
+	}
+	m.history = append(m.history, e)
+	if size := m.cfg.historySize(); len(m.history) > size {
+		m.history = m.history[len(m.history)-size:]
+	}
+	m.historyPos = len(m.history) - 1
+}
+
+// historyBack steps back to the previous history entry, like a browser's
+// back button. From the list, the first step reopens the item last viewed.
+func (m *Model) historyBack() tea.Cmd {
+	if len(m.history) == 0 {
+		m.status = "No history yet"
+		return nil
+	}
+	if len(m.tables) == 0 {
+		m.status = "No table selected"
+		return nil
+	}
+	target := m.historyPos - 1
+	if m.mode != ModeItemView && m.history[m.historyPos].key != nil {
+		target = m.historyPos
+	}
+	if target < 0 {
+		m.status = "At the start of the history"
+		return nil
+	}
+	m.historyPos = target
+	e := m.history[target]
+	m.status = fmt.Sprintf("History %d/%d: %s", target+1, len(m.history), e.label)
+
+	switched := false
+	for i, table := range m.tables {
+		if table.Name == e.table && i != m.currentTable {
+			m.currentTable, switched = i, true
+			m.clearPending()
+			m.drillBack = nil
+			m.isFiltered = false
+			m.filters = nil
+		}
+	}
+	if m.tables[m.currentTable].Name != e.table {
+		m.status = "Table " + e.table + " is gone"
+		return nil
+	}
+	m.mode = ModeNormal
+	m.viewContent = ""
+	m.focusPath = ""
+	m.showDataTypes = false
+
+	if e.key == nil {
+		m.preserveStatus = true
+		return m.runQuery(e.op)
+	}
+	// Reopen the item in place when it's in the list, otherwise load it
+	key := m.itemKey(e.key)
+	for i, item := range m.getFilteredItems() {
+		if switched || m.itemKey(item) != key {
+			continue
+		}
+		m.cursor, m.onHeader = i, false
+		if m.needsFullItem(item) {
+			return m.fetchFullItem(item, fullItemView)
+		}
+		m.viewContent = m.itemViewContent(item)
+		m.mode = ModeItemView
+		return nil
+	}
+	table := m.tables[m.currentTable]
+	m.isFiltered = false
+	m.filters = nil
+	m.preserveStatus = true
+	return m.withSpinner(func() tea.Msg {
+		item, err := m.ddb.GetItem(m.ctx, table.Name, e.key)
+		if err != nil || item == nil {
+			return itemsLoadedMsg{items: []map[string]types.AttributeValue{}, err: err, noMatch: true, desc: "get " + e.label}
+		}
+		return itemsLoadedMsg{items: []map[string]types.AttributeValue{item}, desc: "get " + e.label, view: true}
+	})
+}
@@ -4,35 +4,135 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// Build metadata, set at build time with
+// -ldflags "-X main.version=... -X main.commit=... -X main.date=..."
+var (
+	version = "dev"
+	commit  = "none"
+	date    = "unknown"
+)
+
+// preflightTimeout bounds the startup connectivity check
+const preflightTimeout = 5 * time.Second
+
+func versionString() string {
+	return fmt.Sprintf("dui %s (commit %s, built %s)", version, commit, date)
+}
+
 func main() {
 	endpoint := flag.String("e", "", "DynamoDB endpoint (default: http://localhost:8000)")
 	tableName := flag.String("t", "", "Table name to select on startup")
+	insecure := flag.Bool("insecure", false, "Skip TLS certificate verification (local https endpoints only)")
+	showVersion := flag.Bool("version", false, "Print version and exit")
+	scanTable := flag.String("scan", "", "Scan `table` [index], print items, and exit")
+	queryTable := flag.String("query", "", "Query `table` [index] pk=value [sk<op>value], print items, and exit")
+	noAltScreen := flag.Bool("no-altscreen", false, "Render inline instead of in the alternate screen, keeping scrollback and terminal mouse selection")
+	watch := flag.Duration("watch", 0, "Refresh the item list every `interval`, e.g. 5s")
+	tablePrefix := flag.String("table-prefix", "", "List only tables whose names start with `prefix` (overrides table_prefix in the config)")
+	useAWS := flag.Bool("aws", false, "Use AWS credentials from the environment, AWS_PROFILE, or instance role; without -e, connect to real DynamoDB")
+	noPreflight := flag.Bool("no-preflight", false, "Start the TUI without first checking that the endpoint answers")
+	docker := flag.Bool("docker", false, "Connect to the DynamoDB Local running in Docker, finding its port (same as -e docker://)")
+	readOnly := flag.Bool("read-only", false, "Refuse every write, including /truncate (same as read_only in the config)")
+	output := flag.String("output", "jsonl", "Output `format` of -scan and -query: jsonl, json, dynamodb-jsonl, or dynamodb-json")
 	flag.Parse()
 
-	// Resolve endpoint: flag > env > default
+	if *showVersion {
+		fmt.Println(versionString())
+		return
+	}
+
+	// Resolve endpoint: flag > env > default, with -docker beating the env
+	if *docker && *endpoint != "" {
+		fmt.Fprintln(os.Stderr, "-docker and -e can't be used together")
+		os.Exit(2)
+	}
+	var err error
 	ep := *endpoint
+	if *docker {
+		ep = dockerScheme
+	}
 	if ep == "" {
 		ep = os.Getenv("DDB_ENDPOINT")
 	}
-	if ep == "" {
+	if ep == "" && !*useAWS {
 		ep = "http://localhost:8000"
 	}
+	if name, ok := strings.CutPrefix(ep, dockerScheme); ok {
+		ctx, cancel := context.WithTimeout(context.Background(), preflightTimeout)
+		ep, err = dockerEndpoint(ctx, name)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to find DynamoDB in Docker: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
-	db, err := NewDB(ep)
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+	if *tablePrefix != "" {
+		cfg.TablePrefix = *tablePrefix
+	}
+	if *readOnly {
+		cfg.ReadOnly = true
+	}
+
+	db, err := NewDB(ep, "", *insecure, *useAWS)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to connect to DynamoDB: %v\n", err)
 		os.Exit(1)
 	}
 
-	m := NewModel(db, *tableName)
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	// Non-interactive mode: print results and exit without the TUI
+	if *scanTable != "" || *queryTable != "" {
+		op, err := batchOperation(*scanTable, *queryTable, flag.Args())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(2)
+		}
+		format, err := parseOutputFormat(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(2)
+		}
+		if err := runBatch(db, op, format, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if !*noPreflight {
+		ctx, cancel := context.WithTimeout(context.Background(), preflightTimeout)
+		err := db.Ping(ctx)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n(use -no-preflight to start anyway)\n", err)
+			os.Exit(1)
+		}
+	}
+
+	m := NewModel(db, cfg, *tableName)
+	m.watchInterval = *watch
+	// Inline mode is for terminal-native selection and copy, so it leaves
+	// the mouse to the terminal too
+	var opts []tea.ProgramOption
+	if !*noAltScreen {
+		opts = append(opts, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	}
+	p := tea.NewProgram(m, opts...)
 
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
@@ -5,13 +5,27 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
+	"github.com/atotto/clipboard"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -27,10 +41,17 @@ const (
 	ModeHelp
 	ModeErrorView
 	ModeFilter
+	ModeInfo
+	ModeConfirmSave
+	ModeConfirmRename
+	ModeConfirmTruncate
+	ModePaste
+	ModeStream
 )
 
 type Model struct {
 	ddb            *DDB
+	cfg            *Config
 	tables         []*TableInfo
 	currentTable   int
 	requestedTable string
@@ -42,27 +63,129 @@ type Model struct {
 	width  int
 	height int
 
-	mode      Mode
-	input     textinput.Model
-	keyBuffer string
+	mode        Mode
+	input       textinput.Model
+	keyBuffer   string
+	lastCommand string // last command run, for @ to edit and re-run
+
+	// commandFromView is set while a command typed in the item view is
+	// edited, so Esc returns to the view
+	commandFromView bool
 
 	status string
 	err    error
 
+	// Loading state: spinner runs while a load is in flight, loadedCount and
+	// loadedPages are the running item and page counts of a paginated scan
+	// or query. The load updates them from its goroutine; the spinner's
+	// ticks redraw the progress.
+	loading     bool
+	spinner     spinner.Model
+	loadedCount atomic.Int64
+	loadedPages atomic.Int64
+
 	viewContent     string
 	editTmpFile     string
 	editOrigContent string
 	editOrigItem    map[string]types.AttributeValue
+	editNative      bool                            // editing DynamoDB JSON rather than simplified JSON
+	pendingItem     map[string]types.AttributeValue // edited item not yet written
+	pendingOrig     map[string]types.AttributeValue // stored item pendingItem replaces, nil if new
+	pendingTable    string                          // table pendingItem is for
+	pendingDB       *DDB                            // endpoint pendingItem is for
+	forceWrite      bool                            // overwrite pendingItem despite concurrent changes
+	quitAfterWrite  bool
+	infoReturnMode  Mode
+	infoScroll      int    // first line of the info overlay shown
+	focusPath       string // path of the subtree the item view shows, "" for all
+	deleteScroll    int    // first key shown by the delete confirmation
 	preserveStatus  bool
 	lastError       string
+	errKind         errorKind // category of err, for its color and hint
 
 	// Filter state
 	filterInput textinput.Model
-	filters     map[string]string
+	filters     []filterClause
 	isFiltered  bool
 
 	// Data type view state
 	showDataTypes bool
+
+	// Expanded row state: show the full JSON of the cursor row, wrapped or
+	// indented like the item view
+	expandRow      bool
+	expandIndented bool
+
+	// wrapList wraps the JSON column of every row instead of truncating it
+	wrapList bool
+
+	// Presentation of values in the list and item view
+	display displayOptions
+
+	// Last scan or query, for copying as an AWS CLI command
+	lastOp *operation
+
+	// What the loaded items are, e.g. "scan" or "get x", and when they were
+	// loaded, shown in the header
+	viewDesc string
+	loadedAt time.Time
+
+	// emptyTable is set when the last load scanned the whole table and found
+	// nothing, for the empty-table hint
+	emptyTable bool
+
+	// Attribute rename waiting for confirmation
+	rename *pendingRename
+
+	// Table truncate waiting for the table name to be typed
+	truncate *pendingTruncate
+
+	// In-TUI JSON insert and its last parse error
+	paste    textarea.Model
+	pasteErr string
+
+	// Client-side order of loaded items, set by /sort, and whether it's
+	// reversed
+	sortOrder []sortCriterion
+	reversed  bool
+	// The items in the order they were loaded, for going back to it
+	loadedOrder []map[string]types.AttributeValue
+
+	// Grouping of the list by partition key: collapsed partitions by key
+	// value, and whether the cursor is on the header of its item's partition
+	grouped   bool
+	collapsed map[string]bool
+	onHeader  bool
+
+	// Filter typed in the table selector
+	tableQuery string
+
+	// Favorite tables of the current endpoint, pinned to the top of the
+	// table selector
+	favorites map[string]bool
+
+	// List to return to after drilling into a partition
+	drillBack *listSnapshot
+
+	// Items viewed and partitions drilled into, oldest first, and the entry
+	// backspace last stepped back to
+	history    []historyEntry
+	historyPos int
+
+	// Watch mode: re-run the last scan or query every watchInterval (0 is
+	// off). watchGen invalidates the ticks of a stopped watch.
+	watchInterval time.Duration
+	watchGen      int
+
+	// stream is the /stream being tailed, nil if none. streamGen
+	// invalidates the reads of a stopped one.
+	stream    *streamTail
+	streamGen int
+
+	// ctx is the parent of every request; cancel aborts them when switching
+	// endpoint or region
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 // Messages
@@ -72,28 +195,98 @@ type tablesLoadedMsg struct {
 }
 
 type itemsLoadedMsg struct {
-	items   []map[string]types.AttributeValue
-	err     error
-	noMatch bool
+	items    []map[string]types.AttributeValue
+	err      error
+	noMatch  bool
+	notFound []string
+	// capped is set when loading stopped at the max_items limit
+	capped bool
+	// emptyTable is set when a scan of the whole table found no items
+	emptyTable bool
+	// refresh is set for watch mode reloads of the current list
+	refresh bool
+	// desc describes what was loaded, for the header breadcrumb
+	desc string
+	// head or tail keep only the first or last that many items, by /head and
+	// /tail
+	head, tail int
+	// view opens the first item in the item view
+	view bool
 }
 
 type operationDoneMsg struct {
 	status string
 	err    error
+
+	// wrote is set when the operation wrote the pending item
+	wrote bool
+}
+
+// writeConflictMsg reports that the stored item changed after it was read,
+// with current the item as it's stored now (nil if it was deleted)
+type writeConflictMsg struct {
+	current map[string]types.AttributeValue
+}
+
+// countLoadedMsg carries a server-side count: count items matched out of
+// scanned items read, which differ when a filter is applied
+type countLoadedMsg struct {
+	count    int
+	scanned  int
+	filtered bool
+	err      error
+}
+
+// describeLoadedMsg carries a table's DescribeTable output as JSON
+type describeLoadedMsg struct {
+	content string
+	err     error
+}
+
+// ttlLoadedMsg carries the TTL attribute of a table for the expired items
+// filter, "" if TTL isn't enabled
+type ttlLoadedMsg struct {
+	table string
+	attr  string
+	err   error
 }
 
 type editorFinishedMsg struct {
 	content  string
 	original string
 	err      error
+	// early is set when the editor exited within a second, and waitFlag is
+	// the flag a GUI editor needed to wait for the edit
+	early    bool
+	waitFlag string
+	// exitCode is the editor's non-zero exit status, as from vim's :cq, which
+	// cancels the edit
+	exitCode int
+}
+
+// fullItemMsg carries an item of a keys-only list fetched in full, and what
+// to do with it
+type fullItemMsg struct {
+	item   map[string]types.AttributeValue
+	err    error
+	action fullItemAction
 }
 
+// fullItemAction is what to do with an item once it's fetched in full
+type fullItemAction int
+
+const (
+	fullItemView fullItemAction = iota
+	fullItemEdit
+	fullItemEditNative
+)
+
 type itemFetchedForEditMsg struct {
 	item map[string]types.AttributeValue
 	err  error
 }
 
-func NewModel(ddb *DDB, requestedTable string) *Model {
+func NewModel(ddb *DDB, cfg *Config, requestedTable string) *Model {
 	ti := textinput.New()
 	ti.Placeholder = "~"
 	ti.CharLimit = 256
@@ -101,29 +294,86 @@ func NewModel(ddb *DDB, requestedTable string) *Model {
 	ti.Focus()
 
 	fi := textinput.New()
-	fi.Placeholder = "attr1=value1, attr2=value2, ..."
+	fi.Placeholder = "attr1=value1, attr2?, !attr3?, ..."
 	fi.CharLimit = 512
 	fi.Width = 60
 
+	sp := spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(cursorStyle))
+	ctx, cancel := context.WithCancel(context.Background())
+
 	return &Model{
+		ctx:            ctx,
+		cancel:         cancel,
 		ddb:            ddb,
+		cfg:            cfg,
 		requestedTable: requestedTable,
 		selected:       make(map[int]bool),
 		input:          ti,
 		filterInput:    fi,
-		filters:        make(map[string]string),
+		spinner:        sp,
+		display:        newDisplayOptions(cfg),
+		paste:          newPasteArea(),
 		status:         "Loading tables...",
 	}
 }
 
 func (m *Model) Init() tea.Cmd {
-	return m.loadTables
+	if m.watchInterval > 0 {
+		return tea.Batch(m.withSpinner(m.loadTables), m.startWatch(m.watchInterval))
+	}
+	return m.withSpinner(m.loadTables)
+}
+
+// withSpinner marks a load as in flight and keeps the spinner ticking until
+// its result message arrives
+func (m *Model) withSpinner(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	m.loadedCount.Store(0)
+	m.loadedPages.Store(0)
+	if m.loading {
+		return cmd
+	}
+	m.loading = true
+	return tea.Batch(cmd, m.spinner.Tick)
+}
+
+// countPage records the running item count of a paginated load, called
+// once per page
+func (m *Model) countPage(count int) {
+	m.loadedCount.Store(int64(count))
+	m.loadedPages.Add(1)
 }
 
 func (m *Model) setError(err error) {
 	errStr := err.Error()
-	m.lastError = errStr
 	m.err = err
+	// HTTP status and request ID, for reporting the error to AWS
+	errStr += requestDetails(err)
+	m.errKind = classifyError(err)
+	if hint := m.errKind.hint(); hint != "" {
+		errStr = hint + "\n\n" + errStr
+		m.lastError = errStr
+		m.status = hint + " (/err)"
+		if m.errKind != errThrottled {
+			// Throttling passes; anything else deserves the details
+			m.viewContent = errStr
+			m.mode = ModeErrorView
+		}
+		return
+	}
+	// Lead with the actionable part of validation errors; the SDK wraps it in
+	// operation and request details
+	if summary, ok := validationMessage(err); ok {
+		errStr = "Invalid request: " + summary + "\n\n" + errStr
+		if len(summary) <= 50 {
+			m.lastError = errStr
+			m.status = summary
+			return
+		}
+	}
+	m.lastError = errStr
 	// Truncate for status line, show full error in window
 	if len(errStr) > 50 {
 		m.status = errStr[:47] + "... (/err)"
@@ -134,20 +384,84 @@ func (m *Model) setError(err error) {
 	}
 }
 
+// loadedTablesStatus reports the tables loaded and how many of them
+// couldn't be described
+func loadedTablesStatus(tables, undescribed int) string {
+	if undescribed == 0 {
+		return fmt.Sprintf("Loaded %d tables", tables)
+	}
+	return fmt.Sprintf("Loaded %d tables, %d with unknown schema (/err for details)", tables, undescribed)
+}
+
+// missingSchema reports whether the current table's keys are unknown,
+// telling the user how to set them
+func (m *Model) missingSchema() bool {
+	if len(m.tables) == 0 || m.tables[m.currentTable].PartitionKey != "" {
+		return false
+	}
+	m.status = fmt.Sprintf("Keys of %s are unknown: set them with /keys pk [sk]", m.tables[m.currentTable].Name)
+	return true
+}
+
+// readOnly reports whether writes are disabled by -read-only or read_only,
+// telling the user so
+func (m *Model) readOnly() bool {
+	if !m.cfg.ReadOnly {
+		return false
+	}
+	m.status = "Read-only mode: writes are disabled"
+	return true
+}
+
+// requestDetails returns the HTTP status and AWS request ID of a failed
+// request, for support cases, or "" if the error carries none
+func requestDetails(err error) string {
+	var respErr *awshttp.ResponseError
+	if !errors.As(err, &respErr) {
+		return ""
+	}
+	details := fmt.Sprintf("\n\nHTTP status: %d", respErr.HTTPStatusCode())
+	if id := respErr.ServiceRequestID(); id != "" {
+		details += "\nRequest ID: " + id
+	}
+	return details
+}
+
+// validationMessage extracts the service's message from a ValidationException,
+// e.g. "Type mismatch for key id expected: S actual: N"
+func validationMessage(err error) (string, bool) {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "ValidationException" {
+		return "", false
+	}
+	msg := strings.TrimPrefix(apiErr.ErrorMessage(), "One or more parameter values were invalid: ")
+	return msg, true
+}
+
 func (m *Model) loadTables() tea.Msg {
-	ctx := context.Background()
+	ctx := m.ctx
 
 	tableNames, err := m.ddb.ListTables(ctx)
 	if err != nil {
 		return tablesLoadedMsg{err: err}
 	}
+	if prefix := m.cfg.TablePrefix; prefix != "" {
+		tableNames = slices.DeleteFunc(tableNames, func(name string) bool {
+			return !strings.HasPrefix(name, prefix)
+		})
+	}
 
+	// A table that can't be described, e.g. for lack of permission, is
+	// still listed so it can be scanned
 	var tables []*TableInfo
 	for _, name := range tableNames {
 		info, err := m.ddb.DescribeTable(ctx, name)
-		if err != nil {
+		if errors.Is(err, context.Canceled) {
 			return tablesLoadedMsg{err: err}
 		}
+		if err != nil {
+			info = &TableInfo{Name: name, DescribeErr: err}
+		}
 		tables = append(tables, info)
 	}
 
@@ -155,12 +469,83 @@ func (m *Model) loadTables() tea.Msg {
 }
 
 func (m *Model) loadItems(tableName string, indexName string) tea.Cmd {
-	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-		defer cancel()
-		items, err := m.ddb.Scan(ctx, tableName, indexName)
-		return itemsLoadedMsg{items: items, err: err}
+	op := &operation{kind: "scan", table: tableName, index: indexName, maxItems: m.cfg.maxItems()}
+	m.lastOp = op
+	m.drillBack = nil
+	return m.withSpinner(func() tea.Msg {
+		items, err := m.ddb.Run(m.ctx, op, m.countPage)
+		return itemsLoadedMsg{items: items, err: err, desc: op.describe(), capped: op.maxItems > 0 && len(items) >= op.maxItems,
+			emptyTable: err == nil && len(items) == 0 && op.readsWholeTable()}
+	})
+}
+
+// loadKeys scans only the key attributes of the current table, for fast
+// browsing of tables with large items. Items are fetched in full when
+// viewed or edited.
+func (m *Model) loadKeys() tea.Cmd {
+	if m.missingSchema() {
+		return nil
+	}
+	op := keysOnlyScan(m.tables[m.currentTable], m.cfg.maxItems())
+	m.lastOp = op
+	m.drillBack = nil
+	return m.withSpinner(func() tea.Msg {
+		items, err := m.ddb.Run(m.ctx, op, m.countPage)
+		return itemsLoadedMsg{items: items, err: err, desc: op.describe(), capped: op.maxItems > 0 && len(items) >= op.maxItems,
+			emptyTable: err == nil && len(items) == 0 && op.readsWholeTable()}
+	})
+}
+
+// needsFullItem reports whether item came from a keys-only scan and so
+// lacks its other attributes
+func (m *Model) needsFullItem(item map[string]types.AttributeValue) bool {
+	if m.lastOp == nil || m.lastOp.projection == "" || len(m.tables) == 0 {
+		return false
+	}
+	keys := 1
+	if m.tables[m.currentTable].SortKey != "" {
+		keys = 2
+	}
+	return len(item) <= keys
+}
+
+// fetchFullItem gets the full item for a keys-only list entry, then acts on
+// it
+func (m *Model) fetchFullItem(item map[string]types.AttributeValue, action fullItemAction) tea.Cmd {
+	table := m.tables[m.currentTable]
+	key := itemPrimaryKey(table, item)
+	return m.withSpinner(func() tea.Msg {
+		full, err := m.ddb.GetItem(m.ctx, table.Name, key)
+		return fullItemMsg{item: full, err: err, action: action}
+	})
+}
+
+// handleFullItem puts a fetched item in place of its keys-only entry and
+// carries out the action it was fetched for
+func (m *Model) handleFullItem(msg fullItemMsg) tea.Cmd {
+	if msg.err != nil {
+		m.setError(msg.err)
+		return nil
+	}
+	if msg.item == nil {
+		m.status = "Item not found"
+		return nil
+	}
+	key := m.itemKey(msg.item)
+	for i, item := range m.items {
+		if m.itemKey(item) == key {
+			m.items[i] = msg.item
+		}
+	}
+	switch msg.action {
+	case fullItemEdit:
+		return m.editCurrentItem()
+	case fullItemEditNative:
+		return m.editCurrentItemNative()
 	}
+	m.viewContent = m.itemViewContent(msg.item)
+	m.mode = ModeItemView
+	return nil
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -169,21 +554,44 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.input.Width = msg.Width - 4
+		m.filterInput.Width = min(60, max(msg.Width-4, 10))
+		// Below the header and title, above the error and input lines
+		m.paste.SetWidth(max(msg.Width-2, 20))
+		m.paste.SetHeight(max(msg.Height-5, 3))
 		return m, nil
 
 	case tablesLoadedMsg:
+		if errors.Is(msg.err, context.Canceled) {
+			// Aborted by an endpoint switch; the new endpoint's load is running
+			return m, nil
+		}
+		m.loading = false
 		if msg.err != nil {
 			m.setError(msg.err)
 			return m, nil
 		}
 		m.tables = msg.tables
+		m.loadFavorites()
+		var describeErrs []string
+		for _, t := range m.tables {
+			if t.DescribeErr != nil {
+				describeErrs = append(describeErrs, t.DescribeErr.Error())
+			}
+		}
+		if len(describeErrs) > 0 {
+			m.lastError = strings.Join(describeErrs, "\n\n")
+		}
 		if len(m.tables) > 0 {
 			m.currentTable = 0
+			// Without -t, default to the table last used on this endpoint
+			if m.requestedTable == "" {
+				m.currentTable = m.lastUsedTable()
+			}
 			// Try to find requested table
 			if m.requestedTable != "" {
 				found := false
 				for i, t := range m.tables {
-					if t.Name == m.requestedTable {
+					if t.Name == m.requestedTable || m.cfg.tableLabel(t.Name) == m.requestedTable {
 						m.currentTable = i
 						found = true
 						break
@@ -193,60 +601,208 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.status = fmt.Sprintf("Table '%s' not found, using %s", m.requestedTable, m.tables[0].Name)
 					m.preserveStatus = true
 				} else {
-					m.status = fmt.Sprintf("Loaded %d tables", len(m.tables))
+					m.status = loadedTablesStatus(len(m.tables), len(describeErrs))
 				}
 			} else {
-				m.status = fmt.Sprintf("Loaded %d tables", len(m.tables))
+				m.status = loadedTablesStatus(len(m.tables), len(describeErrs))
 			}
+			m.rememberTable()
 			return m, m.loadItems(m.tables[m.currentTable].Name, "")
 		}
 		m.status = "No tables found"
 		return m, nil
 
 	case itemsLoadedMsg:
+		if errors.Is(msg.err, context.Canceled) {
+			// Aborted by an endpoint switch; the new endpoint's load is running
+			return m, nil
+		}
+		m.loading = false
+		m.emptyTable = msg.emptyTable
 		if msg.err != nil {
 			m.setError(msg.err)
 			return m, nil
 		}
+		m.loadedAt = time.Now()
+		m.display.order = m.displayOrder()
+		if msg.refresh {
+			m.applyRefresh(msg.items)
+			return m, nil
+		}
 		m.items = msg.items
+		m.viewDesc = msg.desc
+		m.orderItems()
 		m.cursor = 0
 		m.selected = make(map[int]bool)
-		if msg.noMatch {
+		total := len(m.items)
+		switch {
+		case msg.head > 0:
+			m.items = m.items[:min(msg.head, total)]
+		case msg.tail > 0:
+			m.items = m.items[max(total-msg.tail, 0):]
+		}
+		if msg.head > 0 || msg.tail > 0 {
+			end := "first"
+			if msg.tail > 0 {
+				end = "last"
+			}
+			m.status = fmt.Sprintf("Showing %s %d of %d", end, len(m.items), total)
+			if msg.capped {
+				m.status += " (capped by max_items)"
+			}
+		} else if msg.noMatch && m.preserveStatus {
+			m.preserveStatus = false
+			m.status += " (not found)"
+		} else if msg.noMatch {
 			m.status = "No matching item"
+		} else if msg.capped {
+			m.status = fmt.Sprintf("Showing first %d items of possibly more (raise max_items)", len(m.items))
+		} else if len(msg.notFound) > 0 {
+			m.status = fmt.Sprintf("Loaded %d items, not found: %s", len(m.items), strings.Join(msg.notFound, ", "))
 		} else if m.preserveStatus {
 			m.preserveStatus = false
 		} else {
 			m.status = fmt.Sprintf("Loaded %d items", len(m.items))
 		}
+		if msg.view && len(m.items) > 0 {
+			m.viewContent = m.itemViewContent(m.items[0])
+			m.mode = ModeItemView
+		}
 		return m, nil
 
 	case operationDoneMsg:
+		m.loading = false
 		if msg.err != nil {
+			m.quitAfterWrite = false
 			m.setError(msg.err)
 			return m, nil
 		}
 		m.status = msg.status
 		m.err = nil
+		if msg.wrote {
+			m.clearPending()
+			if m.quitAfterWrite {
+				return m, tea.Quit
+			}
+		}
 		// Reload items after successful operation
 		if len(m.tables) > 0 {
 			return m, m.loadItems(m.tables[m.currentTable].Name, "")
 		}
 		return m, nil
 
+	case writeConflictMsg:
+		m.loading = false
+		m.showWriteConflict(msg.current)
+		return m, nil
+
+	case countLoadedMsg:
+		if errors.Is(msg.err, context.Canceled) {
+			// Aborted by an endpoint switch; the new endpoint's load is running
+			return m, nil
+		}
+		m.loading = false
+		if msg.err != nil {
+			m.setError(msg.err)
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Count: %d items", msg.count)
+		if msg.filtered {
+			m.status = fmt.Sprintf("Count: scanned %d, matched %d", msg.scanned, msg.count)
+			if msg.scanned >= 100 && msg.count*10 < msg.scanned {
+				m.status += " (the filter discards most reads: a key condition or index would be cheaper)"
+			}
+		}
+		return m, nil
+
+	case truncateKeysMsg:
+		if errors.Is(msg.err, context.Canceled) {
+			return m, nil
+		}
+		m.loading = false
+		if msg.err != nil {
+			m.setError(msg.err)
+			return m, nil
+		}
+		m.handleTruncateKeys(msg)
+		return m, nil
+
+	case seedDoneMsg:
+		if errors.Is(msg.err, context.Canceled) {
+			return m, nil
+		}
+		m.loading = false
+		if msg.err != nil {
+			m.setError(msg.err)
+			return m, nil
+		}
+		m.status = msg.status
+		if msg.report != "" {
+			m.showInfo(msg.report)
+		}
+		if msg.wrote {
+			m.preserveStatus = true
+			return m, m.loadItems(m.tables[m.currentTable].Name, "")
+		}
+		return m, nil
+
+	case describeLoadedMsg:
+		if errors.Is(msg.err, context.Canceled) {
+			return m, nil
+		}
+		m.loading = false
+		if msg.err != nil {
+			m.setError(msg.err)
+			return m, nil
+		}
+		m.showInfo(msg.content)
+		return m, nil
+
+	case ttlLoadedMsg:
+		if errors.Is(msg.err, context.Canceled) {
+			return m, nil
+		}
+		m.loading = false
+		if msg.err != nil {
+			m.setError(msg.err)
+			return m, nil
+		}
+		m.applyExpiredFilter(msg.table, msg.attr)
+		return m, nil
+
 	case editorFinishedMsg:
 		if msg.err != nil {
 			m.setError(msg.err)
 			return m, nil
 		}
+		if msg.exitCode != 0 {
+			m.status = fmt.Sprintf("Edit cancelled (editor exited with status %d), no changes made", msg.exitCode)
+			return m, nil
+		}
 		// Check if content changed
 		if msg.content == msg.original {
-			m.status = "No changes made"
+			switch {
+			case msg.waitFlag != "":
+				m.status = fmt.Sprintf("Editor returned before saving: add %s to $EDITOR so it waits", msg.waitFlag)
+			case msg.early:
+				m.status = "No changes made (the editor exited at once: does $EDITOR need a wait flag?)"
+			default:
+				m.status = "No changes made"
+			}
 			return m, nil
 		}
 		// Parse and save the edited item
 		return m, m.saveEditedItem(msg.content)
 
+	case fullItemMsg:
+		if errors.Is(msg.err, context.Canceled) {
+			return m, nil
+		}
+		m.loading = false
+		return m, m.handleFullItem(msg)
+
 	case itemFetchedForEditMsg:
+		m.loading = false
 		if msg.err != nil {
 			m.status = fmt.Sprintf("Error: %v", msg.err)
 			return m, nil
@@ -260,8 +816,43 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.cursor = 0
 		return m, m.editCurrentItem()
 
+	case watchTickMsg:
+		return m, m.handleWatchTick(msg)
+
+	case streamOpenedMsg:
+		return m, m.handleStreamOpened(msg)
+
+	case dockerResolvedMsg:
+		if errors.Is(msg.err, context.Canceled) {
+			return m, nil
+		}
+		m.loading = false
+		if msg.err != nil {
+			m.setError(msg.err)
+			return m, nil
+		}
+		return m, m.switchDB(msg.endpoint, msg.region)
+
+	case streamRecordsMsg:
+		return m, m.handleStreamRecords(msg)
+
+	case streamTickMsg:
+		return m, m.handleStreamTick(msg)
+
+	case spinner.TickMsg:
+		// Let the tick loop die once nothing is loading
+		if !m.loading {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
 	case tea.KeyMsg:
 		return m.handleKeyPress(msg)
+
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
 	}
 
 	return m, nil
@@ -278,6 +869,16 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleItemViewMode(msg)
 	case ModeConfirmDelete:
 		return m.handleConfirmDeleteMode(msg)
+	case ModeConfirmSave:
+		return m.handleConfirmSaveMode(msg)
+	case ModeConfirmRename:
+		return m.handleConfirmRenameMode(msg)
+	case ModeConfirmTruncate:
+		return m.handleConfirmTruncateMode(msg)
+	case ModePaste:
+		return m.handlePasteMode(msg)
+	case ModeStream:
+		return m.handleStreamMode(msg)
 	case ModeFilter:
 		return m.handleFilterMode(msg)
 	case ModeErrorView:
@@ -286,6 +887,26 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.viewContent = ""
 		}
 		return m, nil
+	case ModeInfo:
+		switch msg.String() {
+		case "esc", "enter", "q":
+			m.mode = m.infoReturnMode
+			m.viewContent = ""
+			if m.mode == ModeItemView {
+				m.refreshItemView()
+			}
+		case "j", "down":
+			m.infoScroll = min(m.infoScroll+1, max(strings.Count(m.viewContent, "\n"), 0))
+		case "k", "up":
+			m.infoScroll = max(m.infoScroll-1, 0)
+		case "y":
+			if err := clipboard.WriteAll(m.viewContent); err != nil {
+				m.setError(fmt.Errorf("copy failed: %w", err))
+				return m, nil
+			}
+			m.status = "Copied to clipboard"
+		}
+		return m, nil
 	case ModeHelp:
 		if msg.Type == tea.KeyEsc || msg.String() == "q" || msg.String() == "?" {
 			m.mode = ModeNormal
@@ -316,6 +937,17 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case "@":
+		m.keyBuffer = ""
+		if m.lastCommand == "" {
+			m.status = "No command run yet"
+			return m, nil
+		}
+		m.mode = ModeCommand
+		m.input.SetValue(m.lastCommand)
+		m.input.CursorEnd()
+		return m, nil
+
 	case "/":
 		m.mode = ModeCommand
 		m.input.SetValue("/")
@@ -327,17 +959,33 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "up", "k":
-		if m.cursor > 0 {
-			m.cursor--
-		}
+		m.moveCursor(-1)
 		m.keyBuffer = ""
 		return m, nil
 
 	case "down", "j":
-		items := m.getFilteredItems()
-		if m.cursor < len(items)-1 {
-			m.cursor++
+		m.moveCursor(1)
+		m.keyBuffer = ""
+		return m, nil
+
+	case "l", "right":
+		m.keyBuffer = ""
+		return m, m.drillIntoPartition()
+
+	case "h", "left":
+		m.keyBuffer = ""
+		m.drillOut()
+		return m, nil
+
+	case "ctrl+d", "ctrl+u", "pgdown", "pgup":
+		rows := m.pageRows()
+		if msg.String() == "ctrl+d" || msg.String() == "ctrl+u" {
+			rows = max(rows/2, 1)
+		}
+		if msg.String() == "ctrl+u" || msg.String() == "pgup" {
+			rows = -rows
 		}
+		m.moveCursor(rows)
 		m.keyBuffer = ""
 		return m, nil
 
@@ -349,18 +997,35 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.keyBuffer = ""
 			return m, m.executeCommand(cmd)
 		}
+		if m.onHeader {
+			m.toggleGroup()
+			m.keyBuffer = ""
+			return m, nil
+		}
 		// Otherwise view the selected item
 		item := m.getCurrentItem()
+		if m.needsFullItem(item) {
+			m.keyBuffer = ""
+			m.recordItem(item)
+			return m, m.fetchFullItem(item, fullItemView)
+		}
 		if item != nil {
-			m.viewContent = ItemToPrettyJSON(item)
+			m.recordItem(item)
+			m.viewContent = m.itemViewContent(item)
 			m.mode = ModeItemView
 		}
 		m.keyBuffer = ""
 		return m, nil
 
+	case "backspace":
+		m.keyBuffer = ""
+		return m, m.historyBack()
+
 	case " ":
 		items := m.getFilteredItems()
-		if len(items) > 0 && m.cursor < len(items) {
+		if m.onHeader {
+			m.selectGroup()
+		} else if len(items) > 0 && m.cursor < len(items) {
 			if m.selected[m.cursor] {
 				delete(m.selected, m.cursor)
 			} else {
@@ -378,83 +1043,266 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.keyBuffer = ""
 		return m, nil
 
+	case "E":
+		items := m.getFilteredItems()
+		if len(items) > 0 && len(m.selected) <= 1 {
+			return m, m.editCurrentItemNative()
+		}
+		m.keyBuffer = ""
+		return m, nil
+
 	case "d":
 		if m.keyBuffer == "d" {
 			// dd - delete
-			m.mode = ModeConfirmDelete
 			m.keyBuffer = ""
+			if m.onHeader && len(m.selected) == 0 {
+				m.status = "Space on a partition header selects its items for deleting"
+				return m, nil
+			}
+			m.confirmDelete()
 			return m, nil
 		}
 		m.keyBuffer = "d"
 		return m, nil
 
-	case "t":
-		m.mode = ModeTableSelect
+	case "o":
+		// Cycle: collapsed, wrapped, indented
+		switch {
+		case !m.expandRow:
+			m.expandRow = true
+		case !m.expandIndented:
+			m.expandIndented = true
+		default:
+			m.expandRow, m.expandIndented = false, false
+		}
 		m.keyBuffer = ""
 		return m, nil
 
-	case "i", "a":
+	case ",":
+		m.display.groupNumbers = !m.display.groupNumbers
 		m.keyBuffer = ""
-		return m, m.putNewItem()
+		return m, nil
 
-	case "?":
-		m.mode = ModeHelp
+	case "T":
+		m.display.timestamps = !m.display.timestamps
 		m.keyBuffer = ""
 		return m, nil
 
-	case "f":
-		m.mode = ModeFilter
-		m.filterInput.SetValue("")
-		m.filterInput.Focus()
+	case "C":
+		m.display.colorTypes = !m.display.colorTypes
 		m.keyBuffer = ""
 		return m, nil
 
-	case "s":
+	case "r":
+		m.toggleReverse()
 		m.keyBuffer = ""
-		if len(m.tables) > 0 {
-			return m, m.loadItems(m.tables[m.currentTable].Name, "")
-		}
 		return m, nil
 
-	case "esc":
+	case "O":
 		m.keyBuffer = ""
-		m.input.SetValue("")
-		m.mode = ModeNormal
-		return m, nil
+		return m, m.cycleKeySort()
 
-	case "g":
-		if m.keyBuffer == "g" {
-			m.cursor = 0
-			m.keyBuffer = ""
+	case "X":
+		m.keyBuffer = ""
+		return m, m.toggleExpired()
+
+	case "w":
+		m.wrapList = !m.wrapList
+		if m.wrapList {
+			m.status = "Wrapping rows"
 		} else {
-			m.keyBuffer = "g"
+			m.status = "Truncating rows"
 		}
+		m.keyBuffer = ""
 		return m, nil
 
-	case "G":
-		items := m.getFilteredItems()
-		m.cursor = max(len(items)-1, 0)
+	case "#":
+		m.display.attrCount = !m.display.attrCount
 		m.keyBuffer = ""
 		return m, nil
 
-	default:
+	case "P":
+		m.toggleGrouping()
 		m.keyBuffer = ""
-	}
-
-	return m, nil
-}
-
-func (m *Model) handleCommandMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.Type {
-	case tea.KeyEsc:
-		m.mode = ModeNormal
-		m.input.SetValue("")
 		return m, nil
 
-	case tea.KeyEnter:
-		cmd := m.input.Value()
-		m.input.SetValue("")
+	case "z", "Z":
+		if m.grouped {
+			if msg.String() == "z" {
+				m.toggleGroup()
+			} else {
+				m.toggleAllGroups()
+			}
+		}
+		m.keyBuffer = ""
+		return m, nil
+
+	case "D":
+		m.display.density = m.display.density.next()
+		m.status = "Density: " + m.display.density.String()
+		m.keyBuffer = ""
+		return m, nil
+
+	case "c":
+		m.keyBuffer = ""
+		if m.lastOp == nil {
+			m.status = "No scan or query to copy"
+			return m, nil
+		}
+		command := m.lastOp.cliCommand(m.ddb.endpoint, m.ddb.region)
+		if err := clipboard.WriteAll(command); err != nil {
+			m.setError(fmt.Errorf("copy failed: %w", err))
+			return m, nil
+		}
+		m.status = "Copied AWS CLI " + m.lastOp.kind + " command"
+		return m, nil
+
+	case "t":
+		m.mode = ModeTableSelect
+		m.keyBuffer = ""
+		return m, nil
+
+	case "i", "a":
+		m.keyBuffer = ""
+		return m, m.putNewItem()
+
+	case "I":
+		m.keyBuffer = ""
+		return m, m.startPaste()
+
+	case "?":
+		m.mode = ModeHelp
+		m.keyBuffer = ""
+		return m, nil
+
+	case "f":
+		m.mode = ModeFilter
+		m.filterInput.SetValue("")
+		m.filterInput.Focus()
+		m.keyBuffer = ""
+		return m, nil
+
+	case "s":
+		m.keyBuffer = ""
+		if len(m.tables) > 0 {
+			return m, m.loadItems(m.tables[m.currentTable].Name, "")
+		}
+		return m, nil
+
+	case "esc":
+		m.keyBuffer = ""
+		m.input.SetValue("")
+		m.mode = ModeNormal
+		m.stopWatch()
+		return m, nil
+
+	case "g":
+		if m.keyBuffer == "g" {
+			m.cursor = 0
+			m.onHeader = m.grouped
+			m.keyBuffer = ""
+		} else {
+			m.keyBuffer = "g"
+		}
+		return m, nil
+
+	case "G":
+		if m.grouped {
+			rows := m.groupRows()
+			m.setGroupRow(rows, len(rows)-1)
+		} else {
+			m.cursor = max(len(m.getFilteredItems())-1, 0)
+		}
+		m.keyBuffer = ""
+		return m, nil
+
+	default:
+		m.keyBuffer = ""
+	}
+
+	return m, nil
+}
+
+// pageRows is the number of item rows the list shows at once
+func (m *Model) pageRows() int {
+	return max(m.height-3, 1)
+}
+
+// moveCursor moves the list cursor by delta rows, clamped to the list
+func (m *Model) moveCursor(delta int) {
+	if m.grouped {
+		rows := m.groupRows()
+		m.setGroupRow(rows, m.groupCursorRow(rows)+delta)
+		return
+	}
+	items := m.getFilteredItems()
+	m.cursor = max(min(m.cursor+delta, len(items)-1), 0)
+}
+
+func (m *Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	// Mouse only drives the item list
+	if m.mode != ModeNormal {
+		return m, nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.moveCursor(-1)
+		return m, nil
+
+	case tea.MouseButtonWheelDown:
+		m.moveCursor(1)
+		return m, nil
+
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+		// Rows start below the header line; the list has height-3 visible rows
+		// (header, status line, and the row reserved by renderItems)
+		row, ok := m.rowAt(msg.Y-1, m.height-3)
+		if !ok {
+			return m, nil
+		}
+		m.cursor, m.onHeader = row.idx, row.header
+		m.keyBuffer = ""
+		// Clicking the checkbox glyph toggles selection, of a whole partition
+		// for a header
+		switch {
+		case msg.X >= 2:
+		case row.header:
+			m.selectGroup()
+		case m.selected[row.idx]:
+			delete(m.selected, row.idx)
+		default:
+			m.selected[row.idx] = true
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) handleCommandMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = ModeNormal
+		if m.commandFromView {
+			m.mode = ModeItemView
+		}
+		m.commandFromView = false
+		m.input.SetValue("")
+		return m, nil
+
+	case tea.KeyEnter:
+		cmd := m.input.Value()
+		m.input.SetValue("")
 		m.mode = ModeNormal
+		if m.commandFromView {
+			// :focus reopens the view, anything else leaves it
+			m.commandFromView = false
+			m.viewContent = ""
+			m.focusPath = ""
+			m.showDataTypes = false
+		}
 		return m, m.executeCommand(cmd)
 	}
 
@@ -464,58 +1312,402 @@ func (m *Model) handleCommandMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) handleTableSelectMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "esc":
+	switch msg.Type {
+	case tea.KeyEsc:
+		// Esc clears the filter first, then leaves
+		if m.tableQuery != "" {
+			m.tableQuery = ""
+			return m, nil
+		}
 		m.mode = ModeNormal
 		return m, nil
 
-	case "up", "k":
-		if m.currentTable > 0 {
-			m.currentTable--
+	case tea.KeyUp, tea.KeyDown:
+		matches := m.tableMatches()
+		pos := slices.Index(matches, m.currentTable)
+		if msg.Type == tea.KeyUp && pos > 0 {
+			m.currentTable = matches[pos-1]
+		} else if msg.Type == tea.KeyDown && pos < len(matches)-1 {
+			m.currentTable = matches[pos+1]
 		}
 		return m, nil
 
-	case "down", "j":
-		if m.currentTable < len(m.tables)-1 {
-			m.currentTable++
-		}
+	case tea.KeyCtrlF:
+		m.toggleFavorite()
 		return m, nil
 
-	case "enter":
-		m.mode = ModeNormal
-		if len(m.tables) > 0 {
-			return m, m.loadItems(m.tables[m.currentTable].Name, "")
+	case tea.KeyBackspace:
+		if m.tableQuery != "" {
+			_, size := utf8.DecodeLastRuneInString(m.tableQuery)
+			m.setTableQuery(m.tableQuery[:len(m.tableQuery)-size])
 		}
 		return m, nil
+
+	case tea.KeyRunes, tea.KeySpace:
+		m.setTableQuery(m.tableQuery + string(msg.Runes))
+		return m, nil
+
+	case tea.KeyEnter:
+		if len(m.tableMatches()) == 0 {
+			return m, nil
+		}
+		return m, m.useTable(m.currentTable)
 	}
 	return m, nil
 }
 
+// setTableQuery filters the table selector, moving the cursor to the first
+// match if the current table no longer matches
+func (m *Model) setTableQuery(query string) {
+	m.tableQuery = query
+	matches := m.tableMatches()
+	if len(matches) > 0 && !slices.Contains(matches, m.currentTable) {
+		m.currentTable = matches[0]
+	}
+}
+
+// tableMatches returns the indexes of the tables matching the selector's
+// filter: the filter's characters must appear in the name in order
+func (m *Model) tableMatches() []int {
+	var matches []int
+	for i, t := range m.tables {
+		if fuzzyMatch(m.cfg.tableLabel(t.Name), m.tableQuery) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// executeUse switches to the table named by args[0] and loads its items.
+// An exact name wins, then a unique substring, then a unique fuzzy match as
+// in the table selector.
+func (m *Model) executeUse(args []string) tea.Cmd {
+	if len(args) != 1 {
+		m.status = "Usage: :table name"
+		return nil
+	}
+	if len(m.tables) == 0 {
+		m.status = "No tables loaded"
+		return nil
+	}
+	query := args[0]
+	var substr, fuzzy []int
+	for i, t := range m.tables {
+		label := m.cfg.tableLabel(t.Name)
+		switch {
+		case strings.EqualFold(t.Name, query) || strings.EqualFold(label, query):
+			return m.useTable(i)
+		case strings.Contains(strings.ToLower(label), strings.ToLower(query)):
+			substr = append(substr, i)
+		case fuzzyMatch(label, query):
+			fuzzy = append(fuzzy, i)
+		}
+	}
+	matches := substr
+	if len(matches) == 0 {
+		matches = fuzzy
+	}
+	switch len(matches) {
+	case 0:
+		m.status = "No table matches " + query
+	case 1:
+		return m.useTable(matches[0])
+	default:
+		names := make([]string, 0, len(matches))
+		for _, i := range matches[:min(len(matches), 5)] {
+			names = append(names, m.cfg.tableLabel(m.tables[i].Name))
+		}
+		more := ""
+		if len(matches) > 5 {
+			more = ", ..."
+		}
+		m.status = fmt.Sprintf("%d tables match %s: %s%s", len(matches), query, strings.Join(names, ", "), more)
+	}
+	return nil
+}
+
+// useTable makes table i current and loads its items, as picking it in the
+// table selector does
+func (m *Model) useTable(i int) tea.Cmd {
+	if m.tables[i].Name != m.pendingTable {
+		m.clearPending()
+	}
+	m.currentTable = i
+	m.mode = ModeNormal
+	m.tableQuery = ""
+	m.rememberTable()
+	return m.loadItems(m.tables[i].Name, "")
+}
+
+// fuzzyMatch reports whether the characters of pattern appear in s in
+// order, ignoring case
+func fuzzyMatch(s, pattern string) bool {
+	s = strings.ToLower(s)
+	for _, r := range strings.ToLower(pattern) {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+utf8.RuneLen(r):]
+	}
+	return true
+}
+
+// lastUsedTable returns the index of the table last used on this endpoint,
+// or 0. A remembered table that no longer exists is forgotten.
+func (m *Model) lastUsedTable() int {
+	state, err := loadState()
+	if err != nil {
+		return 0
+	}
+	name, ok := state.LastTables[m.ddb.endpoint]
+	if !ok {
+		return 0
+	}
+	for i, t := range m.tables {
+		if t.Name == name {
+			return i
+		}
+	}
+	delete(state.LastTables, m.ddb.endpoint)
+	state.save() // best effort
+	return 0
+}
+
+// rememberTable records the current table as the last used on this endpoint.
+// Failing to write the state file only costs the convenience, so errors are
+// ignored.
+func (m *Model) rememberTable() {
+	state, err := loadState()
+	if err != nil {
+		return
+	}
+	if state.LastTables == nil {
+		state.LastTables = make(map[string]string)
+	}
+	state.LastTables[m.ddb.endpoint] = m.tables[m.currentTable].Name
+	state.save()
+}
+
+// loadFavorites reads the favorite tables of the endpoint and pins them
+func (m *Model) loadFavorites() {
+	m.favorites = make(map[string]bool)
+	if state, err := loadState(); err == nil {
+		for _, name := range state.Favorites[m.ddb.endpoint] {
+			m.favorites[name] = true
+		}
+	}
+	m.pinFavorites()
+}
+
+// pinFavorites moves favorite tables to the top, keeping the order of each
+// group and the cursor on the same table
+func (m *Model) pinFavorites() {
+	var current *TableInfo
+	if m.currentTable < len(m.tables) {
+		current = m.tables[m.currentTable]
+	}
+	sort.SliceStable(m.tables, func(i, j int) bool {
+		return m.favorites[m.tables[i].Name] && !m.favorites[m.tables[j].Name]
+	})
+	for i, t := range m.tables {
+		if t == current {
+			m.currentTable = i
+		}
+	}
+}
+
+// toggleFavorite pins or unpins the table under the cursor
+func (m *Model) toggleFavorite() {
+	if len(m.tables) == 0 {
+		return
+	}
+	name := m.tables[m.currentTable].Name
+	m.favorites[name] = !m.favorites[name]
+	if !m.favorites[name] {
+		delete(m.favorites, name)
+	}
+
+	state, err := loadState()
+	if err != nil {
+		m.setError(err)
+		return
+	}
+	if state.Favorites == nil {
+		state.Favorites = make(map[string][]string)
+	}
+	var names []string
+	for _, t := range m.tables {
+		if m.favorites[t.Name] {
+			names = append(names, t.Name)
+		}
+	}
+	state.Favorites[m.ddb.endpoint] = names
+	if len(names) == 0 {
+		delete(state.Favorites, m.ddb.endpoint)
+	}
+	if err := state.save(); err != nil {
+		m.setError(err)
+		return
+	}
+
+	m.pinFavorites()
+	if m.favorites[name] {
+		m.status = "Pinned " + name
+	} else {
+		m.status = "Unpinned " + name
+	}
+}
+
 func (m *Model) handleItemViewMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "esc", "q", "enter":
 		m.mode = ModeNormal
 		m.viewContent = ""
+		m.focusPath = ""
 		m.showDataTypes = false
 	case "e":
 		m.mode = ModeNormal
 		m.viewContent = ""
+		m.focusPath = ""
 		m.showDataTypes = false
 		return m, m.editCurrentItem()
+	case "E":
+		m.mode = ModeNormal
+		m.viewContent = ""
+		m.focusPath = ""
+		m.showDataTypes = false
+		return m, m.editCurrentItemNative()
+	case ":":
+		// For :focus; other commands leave the item view
+		m.mode = ModeCommand
+		m.commandFromView = true
+		m.input.SetValue(":")
+		m.input.CursorEnd()
+	case "backspace":
+		if m.focusPath != "" {
+			m.focusPath = ""
+			m.refreshItemView()
+			m.status = "Showing the whole item"
+			return m, nil
+		}
+		return m, m.historyBack()
 	case "x":
 		m.showDataTypes = !m.showDataTypes
+	case "S":
+		if item := m.getCurrentItem(); item != nil {
+			m.showInfo(itemSizeText(item))
+		}
+	case ",":
+		m.display.groupNumbers = !m.display.groupNumbers
+		m.refreshItemView()
+	case "T":
+		m.display.timestamps = !m.display.timestamps
+		m.refreshItemView()
+	case "h":
+		m.display.hideEmpty = !m.display.hideEmpty
+		if m.display.hideEmpty {
+			m.status = "Hiding empty attributes"
+		} else {
+			m.status = "Showing all attributes"
+		}
+		m.refreshItemView()
 	}
 	return m, nil
 }
 
+// showInfo opens the info overlay with content, returning to the current
+// mode when it's closed
+func (m *Model) showInfo(content string) {
+	m.infoReturnMode = m.mode
+	m.infoScroll = 0
+	m.viewContent = content
+	m.mode = ModeInfo
+}
+
+// refreshItemView re-renders the item view after a display option changed
+func (m *Model) refreshItemView() {
+	if item := m.getCurrentItem(); item != nil {
+		m.viewContent = m.itemViewContent(item)
+	}
+}
+
+// confirmDelete asks to confirm deleting the current or selected items
+func (m *Model) confirmDelete() {
+	m.mode = ModeConfirmDelete
+	m.deleteScroll = 0
+	m.input.SetValue("")
+}
+
+// deleteConfirmText is what has to be typed to confirm deleting more items
+// than the confirm_threshold, "" when y will do
+func (m *Model) deleteConfirmText() string {
+	n := len(m.deleteIndexes())
+	if threshold := m.cfg.ConfirmThreshold; threshold <= 0 || n <= threshold {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
 func (m *Model) handleConfirmDeleteMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	typed := m.deleteConfirmText() != ""
+	switch key := msg.String(); {
+	case key == "esc", !typed && (key == "n" || key == "N"):
+		m.mode = ModeNormal
+		m.input.SetValue("")
+		return m, nil
+
+	case !typed && (key == "y" || key == "Y"):
+		m.mode = ModeNormal
+		return m, m.deleteSelectedItems()
+
+	case typed && key == "enter":
+		answer := strings.TrimSpace(m.input.Value())
+		m.mode = ModeNormal
+		m.input.SetValue("")
+		if answer != m.deleteConfirmText() && !strings.EqualFold(answer, "yes") {
+			m.status = "Confirmation didn't match: delete cancelled"
+			return m, nil
+		}
+		return m, m.deleteSelectedItems()
+
+	case typed && !slices.Contains([]string{"down", "up", "pgdown", "pgup", "ctrl+d", "ctrl+u"}, key):
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "j", "down", "k", "up", "ctrl+d", "ctrl+u", "pgdown", "pgup":
+		delta := 1
+		switch msg.String() {
+		case "k", "up":
+			delta = -1
+		case "ctrl+d", "pgdown":
+			delta = m.deletePageRows()
+		case "ctrl+u", "pgup":
+			delta = -m.deletePageRows()
+		}
+		last := max(len(m.deleteIndexes())-m.deletePageRows(), 0)
+		m.deleteScroll = max(min(m.deleteScroll+delta, last), 0)
+	}
+	return m, nil
+}
+
+func (m *Model) handleConfirmSaveMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "y", "Y":
 		m.mode = ModeNormal
-		return m, m.deleteSelectedItems()
+		m.viewContent = ""
+		return m, m.writeItem(m.pendingItem)
 
-	case "n", "N", "esc":
+	case "n", "N", "esc", "q":
+		// Keep the item so :w can still write it
 		m.mode = ModeNormal
+		m.forceWrite = false
+		m.quitAfterWrite = false
+		m.viewContent = ""
+		m.status = "Save cancelled (:w to write it anyway)"
 		return m, nil
 	}
 	return m, nil
@@ -536,7 +1728,7 @@ func (m *Model) handleFilterMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 		if filterStr == "" {
 			// Clear filters
-			m.filters = make(map[string]string)
+			m.filters = nil
 			m.isFiltered = false
 			m.status = "Filters cleared"
 		} else {
@@ -557,137 +1749,689 @@ func (m *Model) handleFilterMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	var cmd tea.Cmd
-	m.filterInput, cmd = m.filterInput.Update(msg)
-	return m, cmd
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) executeCommand(cmd string) tea.Cmd {
+	cmd = strings.TrimSpace(cmd)
+	if cmd != "" && cmd != ":" && cmd != "/" {
+		m.lastCommand = cmd
+	}
+
+	// Handle special commands
+	switch cmd {
+	case ":q", ":quit", "/quit", "/q", "\\q":
+		return tea.Quit
+	case ":?", ":help", "/?", "/help":
+		m.mode = ModeHelp
+		return nil
+	case "/err":
+		if m.lastError != "" {
+			m.viewContent = m.lastError
+			m.mode = ModeErrorView
+		} else {
+			m.status = "No errors"
+		}
+		return nil
+	case "/info":
+		if len(m.tables) == 0 {
+			m.status = "No table selected"
+			return nil
+		}
+		m.showInfo(tableInfoText(m.tables[m.currentTable]))
+		return nil
+	case ":w", ":wq", ":x":
+		if m.pendingItem == nil || len(m.tables) == 0 {
+			if cmd == ":w" {
+				m.status = "Nothing to write"
+				return nil
+			}
+			return tea.Quit
+		}
+		m.quitAfterWrite = cmd != ":w"
+		return m.writeItem(m.pendingItem)
+	case ":q!":
+		return tea.Quit
+	case ":version", "/version":
+		m.status = versionString()
+		return nil
+	case "/mlrd":
+		m.status = "https://mlrd.tech/docs ~ https://mlrd.app"
+		return nil
+	}
+
+	// Parse command
+	fields := strings.Fields(cmd)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	command := strings.ToLower(fields[0])
+	rest := strings.TrimSpace(cmd[len(fields[0]):])
+
+	// These take the rest of the line as is
+	switch command {
+	case "/sort":
+		return m.executeSort(rest)
+	case ":set", "/set":
+		return m.executeSet(rest)
+	}
+
+	args, err := splitArgs(rest)
+	if err != nil {
+		m.setError(err)
+		return nil
+	}
+
+	switch command {
+	case "/scan":
+		if len(args) == 1 && args[0] == "keys" && len(m.tables) > 0 && m.tables[m.currentTable].index("keys") == nil {
+			return m.loadKeys()
+		}
+		indexName := ""
+		if len(args) > 0 {
+			indexName = args[0]
+		}
+		if len(m.tables) > 0 {
+			return m.loadItems(m.tables[m.currentTable].Name, indexName)
+		}
+
+	case "/query":
+		if len(args) < 1 {
+			m.status = "Usage: /query [indexName] pk=value [sk<op>value]"
+			return nil
+		}
+		return m.executeQuery(args)
+
+	case "/sk":
+		return m.executeSortKeyQuery(args)
+
+	case "/count":
+		return m.executeCount(args)
+
+	case "/get":
+		if len(args) < 1 {
+			m.status = "Usage: /get pk [sk] | /get k1 k2 ... | /get pk1:sk1 pk2:sk2 ..."
+			return nil
+		}
+		return m.executeGet(args)
+
+	case "/txget":
+		if len(args) < 1 || len(m.tables) == 0 {
+			m.status = "Usage: /txget k1 k2 ... | /txget pk1:sk1 pk2:sk2 ..."
+			return nil
+		}
+		return m.executeBatchGet(m.tables[m.currentTable], args, true)
+
+	case "/put":
+		if len(args) > 0 {
+			return m.putItemFromArgs(args)
+		}
+		return m.putNewItem()
+
+	case "/update":
+		if len(args) < 1 {
+			m.status = "Usage: /update pk [sk]"
+			return nil
+		}
+		return m.executeUpdate(args)
+
+	case "/endpoint":
+		if len(args) != 1 {
+			m.status = "Endpoint: " + m.ddb.endpoint
+			return nil
+		}
+		return m.switchDB(args[0], m.ddb.region)
+
+	case "/region":
+		if len(args) != 1 {
+			m.status = "Region: " + m.ddb.region
+			return nil
+		}
+		endpoint := m.ddb.endpoint
+		if m.ddb.regional {
+			endpoint = ""
+		}
+		return m.switchDB(endpoint, args[0])
+
+	case "/filter":
+		return m.executeFilter(args)
+
+	case "/grep":
+		return m.executeGrep(args)
+
+	case "/keys":
+		if len(m.tables) == 0 || len(args) < 1 || len(args) > 2 {
+			m.status = "Usage: /keys pk [sk]"
+			return nil
+		}
+		table := m.tables[m.currentTable]
+		table.PartitionKey, table.PartitionKeyType = args[0], ""
+		table.SortKey, table.SortKeyType = "", ""
+		if len(args) == 2 {
+			table.SortKey = args[1]
+		}
+		m.status = "Keys of " + table.Name + ":" + table.keysLabel()
+		return nil
+
+	case ":focus", "/focus":
+		return m.executeFocus(args)
+
+	case "/head", "/tail":
+		return m.executePeek(command, args)
+
+	case "/partitions":
+		if m.missingSchema() {
+			return nil
+		}
+		items := m.getFilteredItems()
+		if len(m.tables) == 0 || len(items) == 0 {
+			m.status = "No items loaded"
+			return nil
+		}
+		n := 20
+		if len(args) > 0 {
+			var err error
+			if n, err = strconv.Atoi(args[0]); err != nil || n < 1 {
+				m.status = "Usage: /partitions [n]"
+				return nil
+			}
+		}
+		m.showInfo(partitionText(items, m.tables[m.currentTable].PartitionKey, n))
+		return nil
+
+	case "/watch":
+		return m.executeWatch(args)
+
+	case "/gostruct":
+		item := m.getCurrentItem()
+		if item == nil {
+			m.status = "No item selected"
+			return nil
+		}
+		if err := clipboard.WriteAll(itemGoCode(item)); err != nil {
+			m.setError(fmt.Errorf("copy failed: %w", err))
+			return nil
+		}
+		m.status = "Copied Go struct and literal of the item"
+		return nil
+
+	case "/describe":
+		tableName := ""
+		if len(args) > 0 {
+			tableName = args[0]
+		} else if len(m.tables) > 0 {
+			tableName = m.tables[m.currentTable].Name
+		}
+		if tableName == "" || len(args) > 1 {
+			m.status = "Usage: /describe [table]"
+			return nil
+		}
+		return m.withSpinner(func() tea.Msg {
+			content, err := m.ddb.DescribeTableJSON(m.ctx, tableName)
+			return describeLoadedMsg{content: content, err: err}
+		})
+
+	case "/truncate":
+		return m.executeTruncate()
+
+	case "/rename":
+		return m.executeRename(args)
+
+	case ":table", "/use":
+		return m.executeUse(args)
+
+	case "/stream":
+		return m.executeStream(args)
+
+	case "/seed":
+		return m.executeSeed(args)
+
+	case "/export":
+		return m.executeExport(args)
+
+	case "/csv":
+		return m.executeCSV(args)
+
+	case "/delete", "/rm":
+		if len(args) < 1 {
+			// Delete current/selected items
+			m.confirmDelete()
+			return nil
+		}
+		return m.executeDelete(args)
+	}
+
+	m.setError(fmt.Errorf("unknown command: %s", command))
+	return nil
 }
 
-func (m *Model) executeCommand(cmd string) tea.Cmd {
-	cmd = strings.TrimSpace(cmd)
-
-	// Handle special commands
-	switch cmd {
-	case ":q", ":quit", "/quit", "/q", "\\q":
-		return tea.Quit
-	case ":?", ":help", "/?", "/help":
-		m.mode = ModeHelp
-		return nil
-	case "/err":
-		if m.lastError != "" {
-			m.viewContent = m.lastError
-			m.mode = ModeErrorView
-		} else {
-			m.status = "No errors"
-		}
-		return nil
-	case "/mlrd":
-		m.status = "https://mlrd.tech/docs ~ https://mlrd.app"
+// executeFilter saves, loads, and lists named filters
+func (m *Model) executeFilter(args []string) tea.Cmd {
+	if len(args) == 0 {
+		m.status = "Usage: /filter save|load name, /filter list"
 		return nil
 	}
 
-	// Parse command
-	parts := strings.Fields(cmd)
-	if len(parts) == 0 {
+	state, err := loadState()
+	if err != nil {
+		m.setError(err)
 		return nil
 	}
 
-	command := strings.ToLower(parts[0])
-	args := parts[1:]
-
-	switch command {
-	case "/scan":
-		indexName := ""
-		if len(args) > 0 {
-			indexName = args[0]
+	switch strings.ToLower(args[0]) {
+	case "list":
+		if len(state.Filters) == 0 {
+			m.status = "No saved filters"
+			return nil
 		}
-		if len(m.tables) > 0 {
-			return m.loadItems(m.tables[m.currentTable].Name, indexName)
+		names := make([]string, 0, len(state.Filters))
+		for name := range state.Filters {
+			names = append(names, name)
 		}
+		sort.Strings(names)
+		m.status = "Filters: " + strings.Join(names, ", ")
 
-	case "/query":
-		if len(args) < 1 {
-			m.status = "Usage: /query [indexName] pk=value"
+	case "save":
+		if len(args) < 2 {
+			m.status = "Usage: /filter save name"
 			return nil
 		}
-		return m.executeQuery(args)
+		if !m.isFiltered || len(m.filters) == 0 {
+			m.status = "No filters to save"
+			return nil
+		}
+		if state.Filters == nil {
+			state.Filters = make(map[string]string)
+		}
+		state.Filters[args[1]] = formatFilters(m.filters)
+		if err := state.save(); err != nil {
+			m.setError(err)
+			return nil
+		}
+		m.status = fmt.Sprintf("Saved filter '%s'", args[1])
 
-	case "/get":
-		if len(args) < 1 {
-			m.status = "Usage: /get pk [sk]"
+	case "load":
+		if len(args) < 2 {
+			m.status = "Usage: /filter load name"
 			return nil
 		}
-		return m.executeGet(args)
+		filterStr, ok := state.Filters[args[1]]
+		if !ok {
+			m.status = fmt.Sprintf("No saved filter '%s'", args[1])
+			return nil
+		}
+		filters, err := m.parseFilters(filterStr)
+		if err != nil {
+			m.status = fmt.Sprintf("Filter error: %v", err)
+			return nil
+		}
+		m.filters = filters
+		m.isFiltered = true
+		m.cursor = 0
+		m.selected = make(map[int]bool)
+		m.status = fmt.Sprintf("Filter '%s' applied: %d criteria", args[1], len(m.filters))
 
-	case "/put":
-		return m.putNewItem()
+	default:
+		m.status = "Usage: /filter save|load name, /filter list"
+	}
+	return nil
+}
 
-	case "/update":
-		if len(args) < 1 {
-			m.status = "Usage: /update pk [sk]"
+// executeSet changes one attribute of the current item. Key attributes can't
+// be updated in place, so changing one writes a new item after confirmation.
+func (m *Model) executeSet(assignment string) tea.Cmd {
+	if m.missingSchema() || m.readOnly() {
+		return nil
+	}
+	item := m.getCurrentItem()
+	if item == nil || len(m.tables) == 0 || len(m.selected) > 1 {
+		m.status = "Select a single item to set an attribute on"
+		return nil
+	}
+	if assignment == "" {
+		m.status = "Usage: :set attr=value"
+		return nil
+	}
+	attr, av, err := ParseAssignment(assignment, item)
+	if err != nil {
+		m.setError(err)
+		return nil
+	}
+
+	table := m.tables[m.currentTable]
+	if attr == table.PartitionKey || attr == table.SortKey {
+		updated := make(map[string]types.AttributeValue, len(item))
+		for k, v := range item {
+			updated[k] = v
+		}
+		updated[attr] = av
+		changes := diffItems(item, updated)
+		if len(changes) == 0 {
+			m.status = "No changes made"
 			return nil
 		}
-		return m.executeUpdate(args)
+		m.stagePending(updated, nil)
+		m.viewContent = fmt.Sprintf("%s is a key attribute: this writes a new item and keeps the original.\n\n%s",
+			attr, strings.Join(changes, "\n"))
+		m.mode = ModeConfirmSave
+		return nil
+	}
 
-	case "/delete", "/rm":
-		if len(args) < 1 {
-			// Delete current/selected items
-			m.mode = ModeConfirmDelete
+	key := map[string]types.AttributeValue{table.PartitionKey: item[table.PartitionKey]}
+	if table.SortKey != "" {
+		key[table.SortKey] = item[table.SortKey]
+	}
+	return m.withSpinner(func() tea.Msg {
+		if err := m.ddb.SetAttribute(m.ctx, table.Name, key, attr, av); err != nil {
+			return operationDoneMsg{err: err}
+		}
+		return operationDoneMsg{status: "Set " + attr}
+	})
+}
+
+// executeGrep filters the loaded items to those with term in any attribute
+// value: /grep [-i] [-e] term, where -i ignores case and -e makes term a regex
+func (m *Model) executeGrep(args []string) tea.Cmd {
+	ignoreCase, regex := m.cfg.IgnoreCase, false
+	for len(args) > 0 && (args[0] == "-i" || args[0] == "-e") {
+		if args[0] == "-i" {
+			ignoreCase = true
+		} else {
+			regex = true
+		}
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		m.status = "Usage: /grep [-i] [-e] term"
+		return nil
+	}
+	term := strings.Join(args, " ")
+
+	clause := filterClause{attr: anyAttr, op: filterMatch, value: term, ignoreCase: ignoreCase}
+	if regex {
+		var err error
+		if clause, err = newRegexClause(anyAttr, term, ignoreCase); err != nil {
+			m.setError(err)
 			return nil
 		}
-		return m.executeDelete(args)
 	}
 
-	m.setError(fmt.Errorf("unknown command: %s", command))
+	m.filters = []filterClause{clause}
+	m.isFiltered = true
+	m.cursor = 0
+	m.selected = make(map[int]bool)
+	m.status = fmt.Sprintf("%d items match %q", len(m.getFilteredItems()), term)
 	return nil
 }
 
+// switchDB reconnects to endpoint and region, aborting in-flight requests on
+// the old client, and reloads the table list
+func (m *Model) switchDB(endpoint, region string) tea.Cmd {
+	if name, ok := strings.CutPrefix(endpoint, dockerScheme); ok {
+		// Asking docker can take seconds, so it runs in the background and
+		// switches once it answers
+		m.status = "Looking for DynamoDB in Docker..."
+		return m.withSpinner(func() tea.Msg {
+			ctx, cancel := context.WithTimeout(m.ctx, preflightTimeout)
+			defer cancel()
+			resolved, err := dockerEndpoint(ctx, name)
+			return dockerResolvedMsg{endpoint: resolved, region: region, err: err}
+		})
+	}
+	ddb, err := NewDB(endpoint, region, m.ddb.insecure, m.ddb.useAWS)
+	if err != nil {
+		m.setError(err)
+		return nil
+	}
+
+	m.cancel()
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.ddb = ddb
+	m.clearPending()
+	// History entries name tables of the old endpoint
+	m.history = nil
+	m.historyPos = 0
+	m.tables = nil
+	m.currentTable = 0
+	m.requestedTable = ""
+	m.items = nil
+	m.cursor = 0
+	m.selected = make(map[int]bool)
+	m.lastOp = nil
+	m.viewDesc = ""
+	m.loadedAt = time.Time{}
+	m.status = "Loading tables from " + ddb.target() + "..."
+	return m.withSpinner(m.loadTables)
+}
+
 func (m *Model) executeQuery(args []string) tea.Cmd {
+	if m.missingSchema() {
+		return nil
+	}
 	if len(m.tables) == 0 {
 		m.status = "No table selected"
 		return nil
 	}
 
 	table := m.tables[m.currentTable]
-	indexName := ""
-	keyArgs := args
-
-	// Check if first arg is an index name
-	if len(args) > 1 && !strings.Contains(args[0], "=") {
-		indexName = args[0]
-		keyArgs = args[1:]
+	op, err := parseQuery(table.Name, args)
+	if err != nil {
+		m.status = fmt.Sprintf("Error: %v", err)
+		return nil
+	}
+	if err := validateQueryKeys(table, op); err != nil {
+		m.setError(err)
+		return nil
 	}
+	return m.runQuery(op)
+}
 
-	if len(keyArgs) == 0 {
-		m.status = "Usage: /query [indexName] pk=value"
+// executeSortKeyQuery narrows the last query's partition by a sort key
+// condition, without repeating the partition key
+func (m *Model) executeSortKeyQuery(args []string) tea.Cmd {
+	last := m.lastOp
+	if len(m.tables) == 0 || last == nil || last.kind != "query" || last.pkValue == nil {
+		m.status = "No partition to narrow: /query or drill into one first"
+		return nil
+	}
+	table := m.tables[m.currentTable]
+	skAttr := table.SortKey
+	if last.index != "" {
+		if idx := table.index(last.index); idx != nil {
+			skAttr = idx.SortKey
+		}
+	}
+	if skAttr == "" {
+		m.status = "No sort key to narrow by"
 		return nil
 	}
 
-	// Parse the key condition
-	pkName, pkValue, err := ParseKeyValue(keyArgs[0])
+	// New values take the type of the sort keys already loaded
+	var sample types.AttributeValue
+	for _, item := range m.items {
+		if sample = item[skAttr]; sample != nil {
+			break
+		}
+	}
+	op, err := sortKeyQuery(last, skAttr, sample, args)
 	if err != nil {
 		m.status = fmt.Sprintf("Error: %v", err)
 		return nil
 	}
+	m.isFiltered = false
+	m.filters = nil
+	return m.runQuery(op)
+}
+
+// runQuery loads the items of a query operation
+func (m *Model) runQuery(op *operation) tea.Cmd {
+	op.maxItems = m.cfg.maxItems()
+	m.lastOp = op
+
+	return m.withSpinner(func() tea.Msg {
+		ctx := m.ctx
+		items, err := m.ddb.Run(ctx, op, m.countPage)
+		return itemsLoadedMsg{items: items, err: err, desc: op.describe(), capped: op.maxItems > 0 && len(items) >= op.maxItems,
+			emptyTable: err == nil && len(items) == 0 && op.readsWholeTable()}
+	})
+}
+
+// executePeek handles /head n and /tail n: it re-runs the last query, or
+// scans the table, and shows only the first or last n items
+func (m *Model) executePeek(command string, args []string) tea.Cmd {
+	n := 10
+	if len(args) > 0 {
+		var err error
+		if n, err = strconv.Atoi(args[0]); err != nil || n < 1 || len(args) > 1 {
+			m.status = fmt.Sprintf("Usage: %s [n]", command)
+			return nil
+		}
+	}
+	if len(m.tables) == 0 {
+		m.status = "No table selected"
+		return nil
+	}
+	op := &operation{kind: "scan", table: m.tables[m.currentTable].Name, maxItems: m.cfg.maxItems()}
+	if m.lastOp != nil && m.lastOp.kind == "query" && !m.lastOp.count {
+		op = m.lastOp
+	}
+	m.lastOp = op
+	head, tail := n, 0
+	if command == "/tail" {
+		head, tail = 0, n
+	}
+	desc := fmt.Sprintf("%s %d of %s", strings.TrimPrefix(command, "/"), n, op.describe())
+	return m.withSpinner(func() tea.Msg {
+		items, err := m.ddb.Run(m.ctx, op, m.countPage)
+		return itemsLoadedMsg{items: items, err: err, desc: desc, head: head, tail: tail,
+			capped: op.maxItems > 0 && len(items) >= op.maxItems}
+	})
+}
+
+// listSnapshot is a loaded list to return to
+type listSnapshot struct {
+	op          *operation
+	desc        string
+	items       []map[string]types.AttributeValue
+	loadedOrder []map[string]types.AttributeValue
+	filters     []filterClause
+	isFiltered  bool
+	cursor      int
+}
+
+// drillIntoPartition queries the partition of the item under the cursor,
+// remembering the current list so drillBack can return to it
+func (m *Model) drillIntoPartition() tea.Cmd {
+	if m.missingSchema() {
+		return nil
+	}
+	item := m.getCurrentItem()
+	if item == nil || len(m.tables) == 0 {
+		return nil
+	}
+	table := m.tables[m.currentTable]
+	if table.SortKey == "" {
+		m.status = "Table has no sort key: each partition is a single item"
+		return nil
+	}
+	pk, ok := item[table.PartitionKey]
+	if !ok {
+		return nil
+	}
+
+	if m.drillBack == nil {
+		m.drillBack = &listSnapshot{op: m.lastOp, desc: m.viewDesc, items: m.items, loadedOrder: m.loadedOrder,
+			filters: m.filters, isFiltered: m.isFiltered, cursor: m.cursor}
+	}
+	var e exprBuilder
+	op := &operation{
+		kind:         "query",
+		table:        table.Name,
+		keyCondition: e.condition(table.PartitionKey, "=", pk),
+		pkAttr:       table.PartitionKey,
+		pkValue:      pk,
+	}
+	op.names, op.values = e.names, e.values
+	m.isFiltered = false
+	m.filters = nil
+	m.record(historyEntry{table: table.Name, op: op, label: "partition " + GetKeyValue(item, table.PartitionKey)})
+	return m.runQuery(op)
+}
+
+// drillOut returns to the list shown before drilling into a partition
+func (m *Model) drillOut() {
+	if m.drillBack == nil {
+		return
+	}
+	m.lastOp = m.drillBack.op
+	m.viewDesc = m.drillBack.desc
+	m.items = m.drillBack.items
+	m.loadedOrder = m.drillBack.loadedOrder
+	m.filters = m.drillBack.filters
+	m.isFiltered = m.drillBack.isFiltered
+	m.cursor = m.drillBack.cursor
+	m.selected = make(map[int]bool)
+	m.drillBack = nil
+	m.status = fmt.Sprintf("%d items", len(m.items))
+}
 
-	keyCondition := fmt.Sprintf("%s = :pk", pkName)
-	exprValues := map[string]types.AttributeValue{
-		":pk": pkValue,
+// executeCount counts matching items server-side without loading them
+func (m *Model) executeCount(args []string) tea.Cmd {
+	if len(m.tables) == 0 {
+		m.status = "No table selected"
+		return nil
 	}
 
-	return func() tea.Msg {
-		ctx := context.Background()
-		items, err := m.ddb.Query(ctx, table.Name, indexName, keyCondition, exprValues)
-		return itemsLoadedMsg{items: items, err: err}
+	table := m.tables[m.currentTable]
+	op, err := parseCount(table.Name, args)
+	if err != nil {
+		m.status = fmt.Sprintf("Error: %v", err)
+		return nil
+	}
+	if op.kind == "query" {
+		if err := validateQueryKeys(table, op); err != nil {
+			m.setError(err)
+			return nil
+		}
+	} else if op.index != "" && table.index(op.index) == nil {
+		m.status = fmt.Sprintf("Table %s has no index named '%s'", table.Name, op.index)
+		return nil
 	}
+	m.lastOp = op
+
+	return m.withSpinner(func() tea.Msg {
+		count, scanned, err := m.ddb.Count(m.ctx, op, m.countPage)
+		return countLoadedMsg{count: count, scanned: scanned, filtered: op.filter != "", err: err}
+	})
 }
 
 func (m *Model) executeGet(args []string) tea.Cmd {
+	if m.missingSchema() {
+		return nil
+	}
 	if len(m.tables) == 0 {
 		m.status = "No table selected"
 		return nil
 	}
 
 	table := m.tables[m.currentTable]
+
+	// "/get pk sk" on a table with a sort key is a single get; anything else
+	// with several args, or any pk:sk arg, is a batch of keys. Without a sort
+	// key a colon is part of the partition key value, as in USER:1.
+	composite := false
+	for _, arg := range args {
+		if _, _, ok := splitCompositeKey(arg); ok && table.SortKey != "" {
+			composite = true
+			break
+		}
+	}
+	if composite || (len(args) > 1 && table.SortKey == "") || len(args) > 2 {
+		return m.executeBatchGet(table, args, false)
+	}
 	key := make(map[string]types.AttributeValue)
 
 	// First arg is partition key value
@@ -698,20 +2442,115 @@ func (m *Model) executeGet(args []string) tea.Cmd {
 		key[table.SortKey] = &types.AttributeValueMemberS{Value: args[1]}
 	}
 
-	return func() tea.Msg {
-		ctx := context.Background()
+	desc := "get " + joinArgs(args)
+	return m.withSpinner(func() tea.Msg {
+		ctx := m.ctx
 		item, err := m.ddb.GetItem(ctx, table.Name, key)
 		if err != nil {
 			return itemsLoadedMsg{err: err}
 		}
 		if item == nil {
-			return itemsLoadedMsg{items: []map[string]types.AttributeValue{}, err: nil, noMatch: true}
+			return itemsLoadedMsg{items: []map[string]types.AttributeValue{}, err: nil, noMatch: true, desc: desc}
+		}
+		return itemsLoadedMsg{items: []map[string]types.AttributeValue{item}, err: nil, desc: desc}
+	})
+}
+
+// executeBatchGet fetches several items by key. Each arg is a partition key
+// value, or pk:sk for tables with a sort key. With transact the items are
+// read with TransactGetItems as one consistent snapshot.
+func (m *Model) executeBatchGet(table *TableInfo, args []string, transact bool) tea.Cmd {
+	if m.missingSchema() {
+		return nil
+	}
+	var keys []map[string]types.AttributeValue
+	var labels []string
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		pk, sk := arg, ""
+		if table.SortKey != "" {
+			var ok bool
+			if pk, sk, ok = splitCompositeKey(arg); !ok {
+				m.status = fmt.Sprintf("Key '%s' needs a sort key: use pk:sk", arg)
+				return nil
+			}
+		}
+		// BatchGetItem and TransactGetItems reject duplicate keys
+		if seen[pk+"\x00"+sk] {
+			continue
+		}
+		seen[pk+"\x00"+sk] = true
+
+		key, err := BuildKey(table, pk, sk)
+		if err != nil {
+			m.status = fmt.Sprintf("Error: %v", err)
+			return nil
+		}
+		keys = append(keys, key)
+		labels = append(labels, arg)
+	}
+
+	desc := "get " + joinArgs(labels)
+	if transact {
+		desc = "txget " + joinArgs(labels)
+	}
+	return m.withSpinner(func() tea.Msg {
+		ctx := m.ctx
+		get := m.ddb.BatchGetItem
+		if transact {
+			get = m.ddb.TransactGetItems
+		}
+		found, err := get(ctx, table.Name, keys)
+		if err != nil {
+			return itemsLoadedMsg{err: err}
+		}
+
+		// Index results by key so they can be returned in the requested order
+		byKey := make(map[string]map[string]types.AttributeValue, len(found))
+		for _, item := range found {
+			byKey[GetKeyValue(item, table.PartitionKey)+"\x00"+GetKeyValue(item, table.SortKey)] = item
+		}
+
+		var items []map[string]types.AttributeValue
+		var notFound []string
+		for i, key := range keys {
+			id := GetKeyValue(key, table.PartitionKey) + "\x00" + GetKeyValue(key, table.SortKey)
+			if item, ok := byKey[id]; ok {
+				items = append(items, item)
+			} else {
+				notFound = append(notFound, labels[i])
+			}
+		}
+		if len(items) == 0 {
+			return itemsLoadedMsg{items: []map[string]types.AttributeValue{}, noMatch: true, desc: desc}
+		}
+		return itemsLoadedMsg{items: items, notFound: notFound, desc: desc}
+	})
+}
+
+// splitCompositeKey splits a pk:sk argument on the first colon that isn't
+// escaped as \:. It reports false if the argument has no such colon.
+func splitCompositeKey(arg string) (pk, sk string, ok bool) {
+	for i := 0; i < len(arg); i++ {
+		switch arg[i] {
+		case '\\':
+			i++ // skip the escaped character
+		case ':':
+			return unescapeKey(arg[:i]), unescapeKey(arg[i+1:]), true
 		}
-		return itemsLoadedMsg{items: []map[string]types.AttributeValue{item}, err: nil}
 	}
+	return "", "", false
+}
+
+// unescapeKey removes the backslash from escaped colons in a key argument
+func unescapeKey(s string) string {
+	return strings.ReplaceAll(s, "\\:", ":")
 }
 
 func (m *Model) executeUpdate(args []string) tea.Cmd {
+	if m.missingSchema() {
+		return nil
+	}
 	if len(m.tables) == 0 {
 		m.status = "No table selected"
 		return nil
@@ -729,17 +2568,20 @@ func (m *Model) executeUpdate(args []string) tea.Cmd {
 	}
 
 	// Get the item first, then the handler will open editor
-	return func() tea.Msg {
-		ctx := context.Background()
+	return m.withSpinner(func() tea.Msg {
+		ctx := m.ctx
 		item, err := m.ddb.GetItem(ctx, table.Name, key)
 		if err != nil {
 			return itemFetchedForEditMsg{err: err}
 		}
 		return itemFetchedForEditMsg{item: item}
-	}
+	})
 }
 
 func (m *Model) executeDelete(args []string) tea.Cmd {
+	if m.missingSchema() || m.readOnly() {
+		return nil
+	}
 	if len(m.tables) == 0 {
 		m.status = "No table selected"
 		return nil
@@ -756,46 +2598,36 @@ func (m *Model) executeDelete(args []string) tea.Cmd {
 		key[table.SortKey] = &types.AttributeValueMemberS{Value: args[1]}
 	}
 
-	return func() tea.Msg {
-		ctx := context.Background()
+	return m.withSpinner(func() tea.Msg {
+		ctx := m.ctx
 		err := m.ddb.DeleteItem(ctx, table.Name, key)
 		if err != nil {
 			return operationDoneMsg{err: err}
 		}
 		return operationDoneMsg{status: "Item deleted"}
-	}
+	})
 }
 
 func (m *Model) deleteSelectedItems() tea.Cmd {
+	if m.missingSchema() || m.readOnly() {
+		return nil
+	}
 	items := m.getFilteredItems()
 	if len(m.tables) == 0 || len(items) == 0 {
 		return nil
 	}
 
 	table := m.tables[m.currentTable]
-
-	// Get items to delete (selected or current)
-	toDelete := make([]int, 0)
-	if len(m.selected) > 0 {
-		for idx := range m.selected {
-			toDelete = append(toDelete, idx)
-		}
-	} else if m.cursor < len(items) {
-		toDelete = append(toDelete, m.cursor)
-	}
-
+	toDelete := m.deleteIndexes()
 	if len(toDelete) == 0 {
 		return nil
 	}
 
-	return func() tea.Msg {
-		ctx := context.Background()
+	return m.withSpinner(func() tea.Msg {
+		ctx := m.ctx
 		deleted := 0
 
 		for _, idx := range toDelete {
-			if idx >= len(items) {
-				continue
-			}
 			item := items[idx]
 
 			// Build key from item
@@ -807,45 +2639,170 @@ func (m *Model) deleteSelectedItems() tea.Cmd {
 				}
 			}
 
-			if err := m.ddb.DeleteItem(ctx, table.Name, key); err != nil {
-				return operationDoneMsg{err: err}
-			}
-			deleted++
+			if err := m.ddb.DeleteItem(ctx, table.Name, key); err != nil {
+				return operationDoneMsg{err: err}
+			}
+			deleted++
+		}
+
+		return operationDoneMsg{status: fmt.Sprintf("Deleted %d item(s)", deleted)}
+	})
+}
+
+// deleteIndexes returns the indexes of the items dd deletes: the selected
+// items, or the item under the cursor
+func (m *Model) deleteIndexes() []int {
+	items := m.getFilteredItems()
+	var indexes []int
+	if len(m.selected) > 0 {
+		for idx := range m.selected {
+			if idx < len(items) {
+				indexes = append(indexes, idx)
+			}
+		}
+		sort.Ints(indexes)
+	} else if m.cursor < len(items) && !m.onHeader {
+		indexes = append(indexes, m.cursor)
+	}
+	return indexes
+}
+
+// putItemFromArgs puts an item built from key=value[:TYPE] arguments
+// without opening the editor
+func (m *Model) putItemFromArgs(args []string) tea.Cmd {
+	if m.missingSchema() {
+		return nil
+	}
+	if len(m.tables) == 0 {
+		m.status = "No table selected"
+		return nil
+	}
+	table := m.tables[m.currentTable]
+
+	item := make(map[string]types.AttributeValue, len(args))
+	for _, arg := range args {
+		name, av, err := ParseTypedKeyValue(arg)
+		if err != nil {
+			m.setError(err)
+			return nil
 		}
-
-		return operationDoneMsg{status: fmt.Sprintf("Deleted %d item(s)", deleted)}
+		item[name] = av
 	}
+	if err := table.checkKeys(item); err != nil {
+		m.setError(err)
+		return nil
+	}
+
+	// Written as a new item, so an existing item with the key is shown as a
+	// conflict to confirm rather than replaced
+	m.stagePending(item, nil)
+	return m.writeItem(item)
 }
 
 func (m *Model) putNewItem() tea.Cmd {
+	if m.missingSchema() {
+		return nil
+	}
 	// Clear original item since this is a new item, not an edit
 	m.editOrigItem = nil
-	// New item template with just primary key attributes
-	var content string
+	m.editNative = false
+	content := "{}"
 	if len(m.tables) > 0 {
-		table := m.tables[m.currentTable]
-		if table.SortKey != "" {
-			content = fmt.Sprintf("{\n  \"%s\": \"\",\n  \"%s\": \"\"\n}", table.PartitionKey, table.SortKey)
-		} else {
-			content = fmt.Sprintf("{\n  \"%s\": \"\"\n}", table.PartitionKey)
-		}
-	} else {
-		content = "{}"
+		content = m.newItemTemplate()
 	}
 	return m.openEditor(content)
 }
 
+// newItemTemplate is the JSON a new item starts from, in the editor and in
+// the insert text area: the current table's keys with empty values
+func (m *Model) newItemTemplate() string {
+	table := m.tables[m.currentTable]
+	keys := map[string]any{table.PartitionKey: ""}
+	order := []string{table.PartitionKey}
+	if table.SortKey != "" {
+		keys[table.SortKey] = ""
+		order = append(order, table.SortKey)
+	}
+	data, err := json.MarshalIndent(orderedJSON(keys, order), "", m.display.indent)
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
 func (m *Model) editCurrentItem() tea.Cmd {
+	if m.missingSchema() {
+		return nil
+	}
 	item := m.getCurrentItem()
 	if item == nil {
 		m.status = "No item selected"
 		return nil
 	}
+	if m.needsFullItem(item) {
+		return m.fetchFullItem(item, fullItemEdit)
+	}
 	m.editOrigItem = item
-	content := ItemToPrettyJSON(item)
+	m.editNative = false
+	content := ItemToPrettyJSON(item, m.display.indent, m.editOrder())
 	return m.openEditor(content)
 }
 
+// editCurrentItemNative opens the current item in the editor as DynamoDB
+// JSON, giving exact control over every attribute's type
+func (m *Model) editCurrentItemNative() tea.Cmd {
+	if m.missingSchema() {
+		return nil
+	}
+	item := m.getCurrentItem()
+	if item == nil {
+		m.status = "No item selected"
+		return nil
+	}
+	if m.needsFullItem(item) {
+		return m.fetchFullItem(item, fullItemEditNative)
+	}
+	m.editOrigItem = item
+	m.editNative = true
+	return m.openEditor(ItemToNativeJSON(item, m.display.indent, m.editOrder()))
+}
+
+// displayOrder is the attribute order of the current table's items when
+// display_order is configured for it: the keys, then the configured
+// attributes. nil sorts attributes by name.
+func (m *Model) displayOrder() []string {
+	if len(m.tables) == 0 {
+		return nil
+	}
+	table := m.tables[m.currentTable]
+	attrs := m.cfg.DisplayOrder[table.Name]
+	if len(attrs) == 0 {
+		return nil
+	}
+	order := []string{table.PartitionKey}
+	if table.SortKey != "" {
+		order = append(order, table.SortKey)
+	}
+	return append(order, attrs...)
+}
+
+// editOrder is the attribute order of the editor's content: the display
+// order if order_edits is set
+func (m *Model) editOrder() []string {
+	if !m.cfg.OrderEdits {
+		return nil
+	}
+	return m.display.order
+}
+
+// parseEditedItem parses editor content in the format it was opened in
+func (m *Model) parseEditedItem(content string) (map[string]types.AttributeValue, error) {
+	if m.editNative {
+		return NativeJSONToItem(content)
+	}
+	return JSONToItem(content, m.editOrigItem)
+}
+
 func (m *Model) openEditor(content string) tea.Cmd {
 	m.editOrigContent = content
 
@@ -853,6 +2810,12 @@ func (m *Model) openEditor(content string) tea.Cmd {
 	if editor == "" {
 		editor = "vim"
 	}
+	// $EDITOR may carry arguments, e.g. "code --wait"
+	editorArgs, err := splitArgs(editor)
+	if err != nil || len(editorArgs) == 0 {
+		m.status = fmt.Sprintf("Invalid $EDITOR %q", editor)
+		return nil
+	}
 
 	// Create temp file
 	tmpFile, err := os.CreateTemp("", "dui-*.json")
@@ -869,11 +2832,17 @@ func (m *Model) openEditor(content string) tea.Cmd {
 	}
 	tmpFile.Close()
 
-	c := exec.Command(editor, m.editTmpFile)
+	c := exec.Command(editorArgs[0], append(editorArgs[1:], m.editTmpFile)...)
 	origContent := content // capture for closure
+	waitFlag := missingWaitFlag(editorArgs)
+	start := time.Now()
 	return tea.ExecProcess(c, func(err error) tea.Msg {
 		if err != nil {
 			os.Remove(m.editTmpFile)
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+				return editorFinishedMsg{exitCode: exitErr.ExitCode()}
+			}
 			return editorFinishedMsg{err: err}
 		}
 
@@ -884,10 +2853,119 @@ func (m *Model) openEditor(content string) tea.Cmd {
 			return editorFinishedMsg{err: err}
 		}
 
-		return editorFinishedMsg{content: string(result), original: origContent}
+		return editorFinishedMsg{
+			content:  string(result),
+			original: origContent,
+			early:    time.Since(start) < time.Second,
+			waitFlag: waitFlag,
+		}
 	})
 }
 
+// guiEditorWaitFlags are the flags that make GUI editors block until the file
+// is closed. Without one they return at once, before any edit is saved.
+var guiEditorWaitFlags = map[string][]string{
+	"code":          {"--wait", "-w"},
+	"code-insiders": {"--wait", "-w"},
+	"codium":        {"--wait", "-w"},
+	"cursor":        {"--wait", "-w"},
+	"subl":          {"--wait", "-w"},
+	"zed":           {"--wait", "-w"},
+	"mate":          {"--wait", "-w"},
+	"atom":          {"--wait", "-w"},
+	"gedit":         {"--wait"},
+	"gvim":          {"--nofork", "-f"},
+	"mvim":          {"--nofork", "-f"},
+}
+
+// missingWaitFlag returns the wait flag a known GUI editor command lacks, or
+// "" if it has one or isn't a known GUI editor
+func missingWaitFlag(editorArgs []string) string {
+	flags, ok := guiEditorWaitFlags[filepath.Base(editorArgs[0])]
+	if !ok {
+		return ""
+	}
+	for _, arg := range editorArgs[1:] {
+		if slices.Contains(flags, arg) {
+			return ""
+		}
+	}
+	return flags[0]
+}
+
+// splitArgs splits command arguments, and $EDITOR, into words. Words are
+// separated by blanks, and single or double quotes group words with blanks
+// in them, like "user 1". A backslash quotes a following quote or blank;
+// other backslashes are kept, so escaped colons in composite keys like
+// a\:b:c, and Windows paths, still work.
+func splitArgs(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			if r != '\'' && r != '"' && r != ' ' && r != '\t' {
+				word.WriteRune('\\')
+			}
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inWord = true, true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in %q", quote, s)
+	}
+	if escaped {
+		word.WriteRune('\\')
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// joinArgs joins command arguments for display, quoting those with blanks or
+// quotes in them
+func joinArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = arg
+		if arg == "" || strings.ContainsAny(arg, " \t'\"") {
+			quoted[i] = strconv.Quote(arg)
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
+// saveEditedItem parses the edited content and asks for confirmation with a
+// diff against the content originally opened in the editor
 func (m *Model) saveEditedItem(content string) tea.Cmd {
 	if len(m.tables) == 0 {
 		return func() tea.Msg {
@@ -895,27 +2973,274 @@ func (m *Model) saveEditedItem(content string) tea.Cmd {
 		}
 	}
 
+	item, err := m.parseEditedItem(content)
+	if err != nil {
+		m.setError(err)
+		return nil
+	}
+	if err := m.tables[m.currentTable].checkKeys(item); err != nil {
+		m.setError(err)
+		return nil
+	}
+
+	// Diff parsed items so formatting-only edits don't count as changes
+	original, err := m.parseEditedItem(m.editOrigContent)
+	if err != nil {
+		original = m.editOrigItem
+	}
+	changes := diffItems(original, item)
+	if len(changes) == 0 {
+		m.status = "No changes made"
+		return nil
+	}
+
+	orig := m.editOrigItem
+	if orig != nil && m.itemKey(item) != m.itemKey(orig) {
+		// A changed key writes a new item alongside the original
+		orig = nil
+	}
+	m.stagePending(item, orig)
+	// Lead with type changes, which are easy to make by accident, like
+	// quoting a number
+	m.viewContent = strings.Join(append(typeChanges("", original, item), changes...), "\n")
+	m.mode = ModeConfirmSave
+	return nil
+}
+
+// stagePending keeps item as the write waiting for confirmation or :w, for
+// the current table. orig is the stored item it replaces, nil if new.
+func (m *Model) stagePending(item, orig map[string]types.AttributeValue) {
+	m.pendingItem, m.pendingOrig = item, orig
+	m.pendingTable, m.pendingDB = m.tables[m.currentTable].Name, m.ddb
+	m.forceWrite = false
+}
+
+// clearPending drops the pending write, once written or when switching to
+// another table or endpoint
+func (m *Model) clearPending() {
+	m.pendingItem, m.pendingOrig = nil, nil
+	m.pendingTable, m.pendingDB = "", nil
+	m.forceWrite = false
+}
+
+// writeItem puts the pending item into the current table. Unless forced
+// by a confirmed overwrite, the write fails with a writeConflictMsg when
+// the stored item changed since it was read. It refuses to write an item
+// staged for another table or endpoint.
+func (m *Model) writeItem(item map[string]types.AttributeValue) tea.Cmd {
+	if m.readOnly() {
+		m.quitAfterWrite = false
+		return nil
+	}
 	table := m.tables[m.currentTable]
-	originalItem := m.editOrigItem
+	if m.pendingDB != m.ddb || m.pendingTable != table.Name {
+		m.quitAfterWrite = false
+		m.status = fmt.Sprintf("The unwritten item is for table %s, not %s: switch back to write it", m.pendingTable, table.Name)
+		return nil
+	}
+	expected, force := m.pendingOrig, m.forceWrite
+
+	return m.withSpinner(func() tea.Msg {
+		ctx := m.ctx
+		if force {
+			if err := m.ddb.PutItem(ctx, table.Name, item); err != nil {
+				return operationDoneMsg{err: err}
+			}
+			return operationDoneMsg{status: "Item saved", wrote: true}
+		}
 
-	return func() tea.Msg {
-		item, err := JSONToItem(content, originalItem)
+		err := m.ddb.PutItemIf(ctx, table, item, expected)
+		var conflict *types.ConditionalCheckFailedException
+		if errors.As(err, &conflict) {
+			current, err := m.ddb.GetItem(ctx, table.Name, itemPrimaryKey(table, item))
+			if err != nil {
+				return operationDoneMsg{err: err}
+			}
+			return writeConflictMsg{current: current}
+		}
 		if err != nil {
 			return operationDoneMsg{err: err}
 		}
 
-		ctx := context.Background()
-		if err := m.ddb.PutItem(ctx, table.Name, item); err != nil {
-			return operationDoneMsg{err: err}
+		return operationDoneMsg{status: "Item saved", wrote: true}
+	})
+}
+
+// showWriteConflict asks whether to overwrite an item that changed since it
+// was read, listing what changed
+func (m *Model) showWriteConflict(current map[string]types.AttributeValue) {
+	var header string
+	var changes []string
+	switch {
+	case m.pendingOrig == nil:
+		header = "An item with this key already exists. Overwriting it changes:"
+		changes = diffItems(current, m.pendingItem)
+	case current == nil:
+		header = "The item was deleted since you opened it. Writing it recreates it."
+	default:
+		header = "The item was changed since you opened it. Their changes:"
+		changes = diffItems(m.pendingOrig, current)
+	}
+	m.viewContent = strings.TrimSpace(header + "\n\n" + strings.Join(changes, "\n"))
+	m.forceWrite = true
+	m.mode = ModeConfirmSave
+}
+
+// diffItems lists the attributes added (+), removed (-), and changed (~)
+// between two items, sorted by attribute name
+func diffItems(before, after map[string]types.AttributeValue) []string {
+	oldValues := attributeValueToInterface(before)
+	newValues := attributeValueToInterface(after)
+
+	names := make(map[string]bool)
+	for k := range oldValues {
+		names[k] = true
+	}
+	for k := range newValues {
+		names[k] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for k := range names {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var changes []string
+	for _, k := range sorted {
+		oldValue, inOld := oldValues[k]
+		newValue, inNew := newValues[k]
+		oldJSON, _ := json.Marshal(oldValue)
+		newJSON, _ := json.Marshal(newValue)
+		switch {
+		case !inOld:
+			changes = append(changes, fmt.Sprintf("+ %s: %s", k, newJSON))
+		case !inNew:
+			changes = append(changes, fmt.Sprintf("- %s: %s", k, oldJSON))
+		case string(oldJSON) != string(newJSON):
+			changes = append(changes, fmt.Sprintf("~ %s: %s → %s", k, oldJSON, newJSON))
+		}
+	}
+	return changes
+}
+
+// typeChanges warns about the attributes, nested ones included, whose type
+// differs between two items, like "! age changed from N to S"
+func typeChanges(prefix string, before, after map[string]types.AttributeValue) []string {
+	names := make([]string, 0, len(after))
+	for k := range after {
+		if _, ok := before[k]; ok {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+
+	var warnings []string
+	for _, k := range names {
+		warnings = append(warnings, valueTypeChanges(prefix+k, before[k], after[k])...)
+	}
+	return warnings
+}
+
+// valueTypeChanges compares the types of an attribute's old and new value,
+// descending into maps and lists whose type stayed the same
+func valueTypeChanges(path string, before, after types.AttributeValue) []string {
+	from, to := typeName(before), typeName(after)
+	if from != to {
+		return []string{fmt.Sprintf("! %s changed from %s to %s", path, from, to)}
+	}
+	switch b := before.(type) {
+	case *types.AttributeValueMemberM:
+		return typeChanges(path+".", b.Value, after.(*types.AttributeValueMemberM).Value)
+	case *types.AttributeValueMemberL:
+		a := after.(*types.AttributeValueMemberL).Value
+		var warnings []string
+		for i := range min(len(b.Value), len(a)) {
+			warnings = append(warnings, valueTypeChanges(fmt.Sprintf("%s[%d]", path, i), b.Value[i], a[i])...)
+		}
+		return warnings
+	}
+	return nil
+}
+
+// typeName is the DynamoDB type of av, like N or M
+func typeName(av types.AttributeValue) string {
+	switch t := attrToType(av).(type) {
+	case string:
+		return t
+	case map[string]any:
+		name, _ := t["type"].(string)
+		return name
+	}
+	return "?"
+}
+
+// filterOp is the comparison applied by a filter clause
+type filterOp int
+
+const (
+	filterMatch      filterOp = iota // attr=value
+	filterExists                     // attr?
+	filterMissing                    // !attr?
+	filterRegex                      // attr~regex
+	filterExpired                    // expired(attr)
+	filterBeginsWith                 // attr begins_with value
+	filterContains                   // attr contains value
+)
+
+// anyAttr is the filter attribute that matches the values of every attribute,
+// including those nested in maps and lists
+const anyAttr = "*"
+
+// filterClause is a single criterion of the filter input
+type filterClause struct {
+	attr       string
+	op         filterOp
+	value      string
+	ignoreCase bool
+	re         *regexp.Regexp
+}
+
+// String returns the clause in filter input syntax
+func (f filterClause) String() string {
+	switch f.op {
+	case filterExists:
+		return f.attr + "?"
+	case filterMissing:
+		return "!" + f.attr + "?"
+	case filterRegex:
+		return f.attr + "~" + f.re.String()
+	case filterExpired:
+		return "expired(" + f.attr + ")"
+	case filterBeginsWith, filterContains:
+		name := "begins_with"
+		if f.op == filterContains {
+			name = "contains"
+		}
+		if f.ignoreCase {
+			return f.attr + " " + name + " " + f.value + "/i"
 		}
+		return f.attr + " " + name + " " + f.value
+	default:
+		if f.ignoreCase {
+			return f.attr + "=" + f.value + "/i"
+		}
+		return f.attr + "=" + f.value
+	}
+}
 
-		return operationDoneMsg{status: "Item saved"}
+// formatFilters returns filter clauses in filter input syntax
+func formatFilters(filters []filterClause) string {
+	parts := make([]string, len(filters))
+	for i, f := range filters {
+		parts[i] = f.String()
 	}
+	return strings.Join(parts, ", ")
 }
 
-// parseFilters parses a CSV string of attribute=value pairs into a map
-func (m *Model) parseFilters(filterStr string) (map[string]string, error) {
-	filters := make(map[string]string)
+// parseFilters parses a CSV string of attribute=value pairs and attr? / !attr?
+// presence checks into filter clauses
+func (m *Model) parseFilters(filterStr string) ([]filterClause, error) {
+	var filters []filterClause
 
 	parts := strings.Split(filterStr, ",")
 	for _, part := range parts {
@@ -924,9 +3249,54 @@ func (m *Model) parseFilters(filterStr string) (map[string]string, error) {
 			continue
 		}
 
+		// TTL checks: expired(attr)
+		if attr, ok := strings.CutPrefix(part, "expired("); ok && strings.HasSuffix(attr, ")") {
+			attr = strings.TrimSpace(strings.TrimSuffix(attr, ")"))
+			if attr == "" {
+				return nil, fmt.Errorf("empty attribute name in filter")
+			}
+			filters = append(filters, filterClause{attr: attr, op: filterExpired})
+			continue
+		}
+
+		// Functions, like DynamoDB's: attr begins_with value, attr contains value
+		if clause, ok := parseFunctionClause(part, m.cfg.IgnoreCase); ok {
+			if clause.attr == "" {
+				return nil, fmt.Errorf("empty attribute name in filter")
+			}
+			filters = append(filters, clause)
+			continue
+		}
+
+		// Presence checks: attr? and !attr?
+		if strings.HasSuffix(part, "?") && !strings.Contains(part, "=") {
+			clause := filterClause{op: filterExists}
+			attr := strings.TrimSuffix(part, "?")
+			if strings.HasPrefix(attr, "!") {
+				clause.op = filterMissing
+				attr = attr[1:]
+			}
+			clause.attr = strings.TrimSpace(attr)
+			if clause.attr == "" {
+				return nil, fmt.Errorf("empty attribute name in filter")
+			}
+			filters = append(filters, clause)
+			continue
+		}
+
+		// Regex matches: attr~regex
+		if i := strings.Index(part, "~"); i >= 0 && !strings.Contains(part[:i], "=") {
+			clause, err := newRegexClause(strings.TrimSpace(part[:i]), strings.TrimSpace(part[i+1:]), false)
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, clause)
+			continue
+		}
+
 		kv := strings.SplitN(part, "=", 2)
 		if len(kv) != 2 {
-			return nil, fmt.Errorf("invalid filter format: '%s' (expected attribute=value)", part)
+			return nil, fmt.Errorf("invalid filter format: '%s' (expected attribute=value, attr~regex, attr begins_with value, attr contains value, attr?, !attr? or expired(attr))", part)
 		}
 
 		key := strings.TrimSpace(kv[0])
@@ -936,7 +3306,14 @@ func (m *Model) parseFilters(filterStr string) (map[string]string, error) {
 			return nil, fmt.Errorf("empty attribute name in filter")
 		}
 
-		filters[key] = value
+		// A trailing /i makes the match case-insensitive
+		ignoreCase := m.cfg.IgnoreCase
+		if strings.HasSuffix(value, "/i") {
+			value = strings.TrimSuffix(value, "/i")
+			ignoreCase = true
+		}
+
+		filters = append(filters, filterClause{attr: key, op: filterMatch, value: value, ignoreCase: ignoreCase})
 	}
 
 	if len(filters) == 0 {
@@ -946,34 +3323,173 @@ func (m *Model) parseFilters(filterStr string) (map[string]string, error) {
 	return filters, nil
 }
 
+// filterFunctions are the names of the function filter operators
+var filterFunctions = []struct {
+	name string
+	op   filterOp
+}{
+	{"begins_with", filterBeginsWith},
+	{"contains", filterContains},
+}
+
+// parseFunctionClause parses "attr begins_with value" or "attr contains
+// value". A trailing /i on the value ignores case.
+func parseFunctionClause(part string, ignoreCase bool) (filterClause, bool) {
+	for _, fn := range filterFunctions {
+		attr, value, ok := strings.Cut(part, " "+fn.name+" ")
+		if !ok || strings.ContainsAny(attr, "=~") {
+			// attr=value contains x is a match on the value
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if strings.HasSuffix(value, "/i") {
+			value = strings.TrimSuffix(value, "/i")
+			ignoreCase = true
+		}
+		return filterClause{attr: strings.TrimSpace(attr), op: fn.op, value: value, ignoreCase: ignoreCase}, true
+	}
+	return filterClause{}, false
+}
+
+// matchFunction evaluates a begins_with or contains clause against an
+// attribute value. begins_with compares the string form; contains looks
+// for a substring of strings and for an element of sets and lists.
+func (f filterClause) matchFunction(av types.AttributeValue) bool {
+	equal := func(a, b string) bool {
+		if f.ignoreCase {
+			return strings.EqualFold(a, b)
+		}
+		return a == b
+	}
+	if f.op == filterBeginsWith {
+		s := filterString(av)
+		return len(s) >= len(f.value) && equal(s[:len(f.value)], f.value)
+	}
+
+	var elems []string
+	switch v := av.(type) {
+	case *types.AttributeValueMemberSS:
+		elems = v.Value
+	case *types.AttributeValueMemberNS:
+		elems = v.Value
+	case *types.AttributeValueMemberL:
+		for _, elem := range v.Value {
+			elems = append(elems, filterString(elem))
+		}
+	default:
+		// Substring of the string form, as =value matches
+		return f.matchValue(filterString(av))
+	}
+	return slices.ContainsFunc(elems, func(elem string) bool { return equal(elem, f.value) })
+}
+
+// newRegexClause compiles a regex filter clause
+func newRegexClause(attr, pattern string, ignoreCase bool) (filterClause, error) {
+	if attr == "" {
+		return filterClause{}, fmt.Errorf("empty attribute name in filter")
+	}
+	if ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return filterClause{}, fmt.Errorf("invalid regex in filter: %w", err)
+	}
+	return filterClause{attr: attr, op: filterRegex, value: pattern, re: re}, nil
+}
+
+// matchValue reports whether an attribute's string form satisfies the clause
+func (f filterClause) matchValue(s string) bool {
+	switch {
+	case f.re != nil:
+		return f.re.MatchString(s)
+	case f.ignoreCase:
+		return strings.Contains(strings.ToLower(s), strings.ToLower(f.value))
+	default:
+		// Substring match, case-sensitive like DynamoDB
+		return strings.Contains(s, f.value)
+	}
+}
+
+// filterString converts an attribute value to the string filters compare
+func filterString(av types.AttributeValue) string {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		return v.Value
+	case *types.AttributeValueMemberN:
+		return v.Value
+	case *types.AttributeValueMemberBOOL:
+		return fmt.Sprintf("%t", v.Value)
+	default:
+		// For complex types, convert to JSON and compare
+		return AttributeValueToString(av)
+	}
+}
+
+// leafValues returns the string form of every scalar value in av, descending
+// into maps and lists
+func leafValues(av types.AttributeValue) []string {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberL:
+		var values []string
+		for _, elem := range v.Value {
+			values = append(values, leafValues(elem)...)
+		}
+		return values
+	case *types.AttributeValueMemberM:
+		var values []string
+		for _, elem := range v.Value {
+			values = append(values, leafValues(elem)...)
+		}
+		return values
+	case *types.AttributeValueMemberSS:
+		return v.Value
+	case *types.AttributeValueMemberNS:
+		return v.Value
+	default:
+		return []string{filterString(av)}
+	}
+}
+
 // matchesFilters checks if an item matches the current filter criteria
 func (m *Model) matchesFilters(item map[string]types.AttributeValue) bool {
 	if !m.isFiltered || len(m.filters) == 0 {
 		return true
 	}
 
-	for attr, filterValue := range m.filters {
-		attrValue, exists := item[attr]
-		if !exists {
-			return false
+	for _, f := range m.filters {
+		if f.attr == anyAttr && (f.op == filterMatch || f.op == filterRegex) {
+			if !matchesAnyValue(item, f) {
+				return false
+			}
+			continue
 		}
 
-		// Convert attribute value to string for comparison
-		var itemValue string
-		switch v := attrValue.(type) {
-		case *types.AttributeValueMemberS:
-			itemValue = v.Value
-		case *types.AttributeValueMemberN:
-			itemValue = v.Value
-		case *types.AttributeValueMemberBOOL:
-			itemValue = fmt.Sprintf("%t", v.Value)
-		default:
-			// For complex types, convert to JSON and compare
-			itemValue = AttributeValueToString(attrValue)
+		attrValue, exists := attrAtPath(item, f.attr)
+		switch f.op {
+		case filterExists:
+			if !exists {
+				return false
+			}
+			continue
+		case filterMissing:
+			if exists {
+				return false
+			}
+			continue
+		case filterExpired:
+			if !exists || !isExpired(attrValue, time.Now()) {
+				return false
+			}
+			continue
+		case filterBeginsWith, filterContains:
+			if !exists || !f.matchFunction(attrValue) {
+				return false
+			}
+			continue
 		}
 
-		// Case-insensitive substring match
-		if !strings.Contains(strings.ToLower(itemValue), strings.ToLower(filterValue)) {
+		if !exists || !f.matchValue(filterString(attrValue)) {
 			return false
 		}
 	}
@@ -981,6 +3497,83 @@ func (m *Model) matchesFilters(item map[string]types.AttributeValue) bool {
 	return true
 }
 
+// attrAtPath looks up a filter attribute, which can be a path into nested
+// maps and lists such as meta.region or items[0].sku. A top-level attribute
+// whose name contains dots or brackets takes precedence. Missing intermediate
+// attributes and out of range indexes don't match.
+func attrAtPath(item map[string]types.AttributeValue, path string) (types.AttributeValue, bool) {
+	if av, ok := item[path]; ok {
+		return av, true
+	}
+	parts, ok := splitPath(path)
+	if !ok {
+		return nil, false
+	}
+	var current types.AttributeValue = &types.AttributeValueMemberM{Value: item}
+	for _, part := range parts {
+		if part.name != "" {
+			m, ok := current.(*types.AttributeValueMemberM)
+			if !ok {
+				return nil, false
+			}
+			if current, ok = m.Value[part.name]; !ok {
+				return nil, false
+			}
+		}
+		for _, n := range part.indexes {
+			l, ok := current.(*types.AttributeValueMemberL)
+			if !ok || n >= len(l.Value) {
+				return nil, false
+			}
+			current = l.Value[n]
+		}
+	}
+	return current, true
+}
+
+// pathPart is one dot-separated part of an attribute path: a map key,
+// followed by any list indexes, like items[0][1]
+type pathPart struct {
+	name    string
+	indexes []int
+}
+
+// splitPath parses an attribute path such as items[0].price, reporting
+// false if an index is malformed or negative
+func splitPath(path string) ([]pathPart, bool) {
+	var parts []pathPart
+	for _, s := range strings.Split(path, ".") {
+		name, rest, _ := strings.Cut(s, "[")
+		part := pathPart{name: name}
+		for rest != "" {
+			idx, after, ok := strings.Cut(rest, "]")
+			n, err := strconv.Atoi(idx)
+			if !ok || err != nil || n < 0 {
+				return nil, false
+			}
+			part.indexes = append(part.indexes, n)
+			if after != "" && !strings.HasPrefix(after, "[") {
+				return nil, false
+			}
+			rest = strings.TrimPrefix(after, "[")
+		}
+		parts = append(parts, part)
+	}
+	return parts, true
+}
+
+// matchesAnyValue reports whether any value in the item satisfies f
+func matchesAnyValue(item map[string]types.AttributeValue, f filterClause) bool {
+	for _, av := range item {
+		for _, v := range leafValues(av) {
+			if f.matchValue(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // getFilteredItems returns the items that match the current filters
 func (m *Model) getFilteredItems() []map[string]types.AttributeValue {
 	if !m.isFiltered {
@@ -995,11 +3588,34 @@ func (m *Model) getFilteredItems() []map[string]types.AttributeValue {
 	return filtered
 }
 
-// getCurrentItem returns the item at the cursor position, respecting filters
+// getCurrentItem returns the item at the cursor position, respecting
+// filters, or nil when the cursor is on a partition header
 func (m *Model) getCurrentItem() map[string]types.AttributeValue {
 	items := m.getFilteredItems()
-	if m.cursor < 0 || m.cursor >= len(items) {
+	if m.onHeader || m.cursor < 0 || m.cursor >= len(items) {
 		return nil
 	}
 	return items[m.cursor]
 }
+
+// targetItems returns the items a bulk command acts on: the selected items
+// when there's a selection, otherwise every loaded item that passes the
+// filters
+func (m *Model) targetItems() []map[string]types.AttributeValue {
+	items := m.getFilteredItems()
+	if len(m.selected) == 0 {
+		return items
+	}
+	indexes := make([]int, 0, len(m.selected))
+	for idx := range m.selected {
+		if idx < len(items) {
+			indexes = append(indexes, idx)
+		}
+	}
+	sort.Ints(indexes)
+	selected := make([]map[string]types.AttributeValue, len(indexes))
+	for i, idx := range indexes {
+		selected[i] = items[idx]
+	}
+	return selected
+}
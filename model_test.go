@@ -0,0 +1,193 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{``, nil},
+		{`a b  c`, []string{"a", "b", "c"}},
+		{"a\tb", []string{"a", "b"}},
+		{`"user 1" "2024 Q1"`, []string{"user 1", "2024 Q1"}},
+		{`'user 1' x`, []string{"user 1", "x"}},
+		{`"it's"`, []string{"it's"}},
+		{`'say "hi"'`, []string{`say "hi"`}},
+		{`''`, []string{""}},
+		{`a"b c"d`, []string{"ab cd"}},
+		{`\"quoted\"`, []string{`"quoted"`}},
+		{`it\'s`, []string{"it's"}},
+		{`"a \" b"`, []string{`a " b`}},
+		{`user\ 1`, []string{"user 1"}},
+		{`a\:b:c`, []string{`a\:b:c`}},
+		{`'a\:b'`, []string{`a\:b`}},
+		{`trailing\`, []string{`trailing\`}},
+		// $EDITOR values
+		{`code --wait`, []string{"code", "--wait"}},
+		{`"/Applications/Sublime Text.app/Contents/SharedSupport/bin/subl" -w`,
+			[]string{"/Applications/Sublime Text.app/Contents/SharedSupport/bin/subl", "-w"}},
+		{`emacsclient -c -a ''`, []string{"emacsclient", "-c", "-a", ""}},
+		{`C:\Tools\edit.exe /w`, []string{`C:\Tools\edit.exe`, "/w"}},
+	}
+	for _, tt := range tests {
+		got, err := splitArgs(tt.in)
+		if err != nil {
+			t.Errorf("splitArgs(%q) error: %v", tt.in, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("splitArgs(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSplitArgsUnterminated(t *testing.T) {
+	for _, in := range []string{`"user 1`, `'user 1`, `a "b\"`} {
+		if got, err := splitArgs(in); err == nil {
+			t.Errorf("splitArgs(%q) = %q, want an unterminated quote error", in, got)
+		}
+	}
+}
+
+func TestJoinArgs(t *testing.T) {
+	tests := []struct {
+		in   []string
+		want string
+	}{
+		{[]string{"a", "b"}, `a b`},
+		{[]string{"user 1", "x"}, `"user 1" x`},
+		{[]string{""}, `""`},
+		{[]string{`say "hi"`}, `"say \"hi\""`},
+		{[]string{"it's"}, `"it's"`},
+	}
+	for _, tt := range tests {
+		if got := joinArgs(tt.in); got != tt.want {
+			t.Errorf("joinArgs(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+		// What joinArgs shows can be typed back in
+		if back, err := splitArgs(joinArgs(tt.in)); err != nil || !slices.Equal(back, tt.in) {
+			t.Errorf("splitArgs(joinArgs(%q)) = %q, %v", tt.in, back, err)
+		}
+	}
+}
+
+func TestSplitPath(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []pathPart
+		ok   bool
+	}{
+		{"name", []pathPart{{name: "name"}}, true},
+		{"meta.region", []pathPart{{name: "meta"}, {name: "region"}}, true},
+		{"items[0].price", []pathPart{{name: "items", indexes: []int{0}}, {name: "price"}}, true},
+		{"orders[2].lines[10].sku", []pathPart{
+			{name: "orders", indexes: []int{2}},
+			{name: "lines", indexes: []int{10}},
+			{name: "sku"},
+		}, true},
+		{"grid[1][2]", []pathPart{{name: "grid", indexes: []int{1, 2}}}, true},
+		{"[0].x", []pathPart{{indexes: []int{0}}, {name: "x"}}, true},
+		{"items[]", nil, false},
+		{"items[-1]", nil, false},
+		{"items[a]", nil, false},
+		{"items[0", nil, false},
+		{"items[0]x", nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := splitPath(tt.in)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitPath(%q) = %+v, %v, want %+v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAttrAtPath(t *testing.T) {
+	s := func(v string) types.AttributeValue { return &types.AttributeValueMemberS{Value: v} }
+	item := map[string]types.AttributeValue{
+		"a.b": s("dotted"),
+		"orders": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+			&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+				"lines": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+					&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"sku": s("A1")}},
+					&types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"sku": s("B2")}},
+				}},
+			}},
+		}},
+	}
+	tests := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{"a.b", "dotted", true},
+		{"orders[0].lines[1].sku", "B2", true},
+		{"orders[0].lines[0].sku", "A1", true},
+		{"orders[0].lines[2].sku", "", false},
+		{"orders[1].lines[0].sku", "", false},
+		{"orders.lines", "", false},
+		{"orders[0].lines[0].sku[0]", "", false},
+	}
+	for _, tt := range tests {
+		av, ok := attrAtPath(item, tt.path)
+		if ok != tt.ok {
+			t.Errorf("attrAtPath(%q) found = %v, want %v", tt.path, ok, tt.ok)
+			continue
+		}
+		if ok && filterString(av) != tt.want {
+			t.Errorf("attrAtPath(%q) = %q, want %q", tt.path, filterString(av), tt.want)
+		}
+	}
+}
+
+func TestParseQueryReservedKeyName(t *testing.T) {
+	// name is a DynamoDB reserved word, so it must go through a placeholder
+	op, err := parseQuery("people", []string{"name=Jo", "status=active"})
+	if err != nil {
+		t.Fatalf("parseQuery error: %v", err)
+	}
+	if want := "#a0 = :v0 AND #a1 = :v1"; op.keyCondition != want {
+		t.Errorf("keyCondition = %q, want %q", op.keyCondition, want)
+	}
+	if want := map[string]string{"#a0": "name", "#a1": "status"}; !reflect.DeepEqual(op.names, want) {
+		t.Errorf("names = %v, want %v", op.names, want)
+	}
+	if v, ok := op.values[":v0"].(*types.AttributeValueMemberS); !ok || v.Value != "Jo" {
+		t.Errorf(":v0 = %#v, want S Jo", op.values[":v0"])
+	}
+
+	op, err = parseQuery("people", []string{"name=Jo"})
+	if err != nil {
+		t.Fatalf("parseQuery error: %v", err)
+	}
+	if want := "#a0 = :v0"; op.keyCondition != want {
+		t.Errorf("keyCondition = %q, want %q", op.keyCondition, want)
+	}
+	if want := map[string]string{"#a0": "name"}; !reflect.DeepEqual(op.names, want) {
+		t.Errorf("names = %v, want %v", op.names, want)
+	}
+}
+
+func TestHandleStreamOpenedKeepsMode(t *testing.T) {
+	// The user opened an item while /stream was starting
+	m := &Model{mode: ModeItemView, streamGen: 1}
+	m.handleStreamOpened(streamOpenedMsg{tail: &streamTail{table: "t", gen: 1, mode: ModeNormal}})
+	if m.mode != ModeItemView || m.stream != nil {
+		t.Errorf("mode = %v, stream = %v, want the item view kept", m.mode, m.stream)
+	}
+
+	m = &Model{mode: ModeNormal, streamGen: 1}
+	m.handleStreamOpened(streamOpenedMsg{tail: &streamTail{table: "t", gen: 1, mode: ModeNormal}})
+	if m.mode != ModeStream || m.stream == nil {
+		t.Errorf("mode = %v, stream = %v, want the stream view", m.mode, m.stream)
+	}
+}
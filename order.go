@@ -0,0 +1,255 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sortCriterion orders items by one attribute
+type sortCriterion struct {
+	attr string
+	desc bool
+}
+
+// parseSortOrder parses "attr1 asc, attr2 desc" into sort criteria. The
+// direction defaults to asc.
+func parseSortOrder(s string) ([]sortCriterion, error) {
+	var order []sortCriterion
+	for _, part := range strings.Split(s, ",") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 || len(fields) > 2 {
+			return nil, fmt.Errorf("invalid sort criterion: %q (expected attr [asc|desc])", strings.TrimSpace(part))
+		}
+		c := sortCriterion{attr: fields[0]}
+		if len(fields) == 2 {
+			switch strings.ToLower(fields[1]) {
+			case "asc":
+			case "desc":
+				c.desc = true
+			default:
+				return nil, fmt.Errorf("invalid sort direction: %q (expected asc or desc)", fields[1])
+			}
+		}
+		order = append(order, c)
+	}
+	return order, nil
+}
+
+// formatSortOrder is the inverse of parseSortOrder
+func formatSortOrder(order []sortCriterion) string {
+	parts := make([]string, len(order))
+	for i, c := range order {
+		parts[i] = c.attr + " asc"
+		if c.desc {
+			parts[i] = c.attr + " desc"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sortItems stably sorts items by order, so items that tie on every
+// criterion keep their loaded order. Items missing an attribute sort after
+// those that have it, whatever the direction.
+func sortItems(items []map[string]types.AttributeValue, order []sortCriterion) {
+	if len(order) == 0 {
+		return
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		for _, c := range order {
+			a, aok := attrAtPath(items[i], c.attr)
+			b, bok := attrAtPath(items[j], c.attr)
+			switch {
+			case !aok && !bok:
+				continue
+			case !aok:
+				return false
+			case !bok:
+				return true
+			}
+			cmp := compareValues(a, b)
+			if cmp == 0 {
+				continue
+			}
+			if c.desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+}
+
+// compareValues orders two attribute values: numbers numerically, binary
+// bytewise, and everything else by its string form. Values of different
+// types order numbers first, then strings, then the rest.
+func compareValues(a, b types.AttributeValue) int {
+	if ra, rb := valueRank(a), valueRank(b); ra != rb {
+		return ra - rb
+	}
+	switch av := a.(type) {
+	case *types.AttributeValueMemberN:
+		x, xok := new(big.Float).SetString(av.Value)
+		y, yok := new(big.Float).SetString(b.(*types.AttributeValueMemberN).Value)
+		if xok && yok {
+			return x.Cmp(y)
+		}
+	case *types.AttributeValueMemberB:
+		return bytes.Compare(av.Value, b.(*types.AttributeValueMemberB).Value)
+	}
+	return strings.Compare(filterString(a), filterString(b))
+}
+
+// valueRank groups attribute types for ordering values of mixed types
+func valueRank(av types.AttributeValue) int {
+	switch av.(type) {
+	case *types.AttributeValueMemberN:
+		return 0
+	case *types.AttributeValueMemberS:
+		return 1
+	case *types.AttributeValueMemberB:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// executeSort sorts the loaded items by one or more attributes. The order
+// sticks across reloads until cleared with /sort off.
+func (m *Model) executeSort(arg string) tea.Cmd {
+	switch strings.ToLower(arg) {
+	case "":
+		if len(m.sortOrder) == 0 {
+			m.status = "Usage: /sort attr [asc|desc], attr2 [asc|desc], ... | /sort off"
+		} else {
+			m.status = "Sorted by " + formatSortOrder(m.sortOrder)
+		}
+		return nil
+	case "off":
+		m.sortOrder = nil
+		m.restoreLoadedOrder()
+		m.status = "Sort cleared"
+		return nil
+	}
+
+	order, err := parseSortOrder(arg)
+	if err != nil {
+		m.setError(err)
+		return nil
+	}
+	m.sortOrder = order
+	m.applyOrder()
+	m.cursor = 0
+	m.selected = make(map[int]bool)
+	m.status = "Sorted by " + formatSortOrder(order)
+	return nil
+}
+
+// orderItems applies the /sort order and the reversed flag to freshly
+// loaded items, keeping a copy in their loaded order
+func (m *Model) orderItems() {
+	m.loadedOrder = slices.Clone(m.items)
+	m.applyOrder()
+}
+
+// applyOrder sorts the items by the /sort order, reversed if the list is
+func (m *Model) applyOrder() {
+	sortItems(m.items, m.sortOrder)
+	if m.reversed {
+		slices.Reverse(m.items)
+	}
+}
+
+// toggleReverse flips the order of the loaded items, keeping the cursor and
+// selection on the same items
+func (m *Model) toggleReverse() {
+	m.reversed = !m.reversed
+	last := len(m.getFilteredItems()) - 1
+	slices.Reverse(m.items)
+	if last >= 0 {
+		m.cursor = last - m.cursor
+	}
+	selected := make(map[int]bool, len(m.selected))
+	for idx := range m.selected {
+		selected[last-idx] = true
+	}
+	m.selected = selected
+	if m.reversed {
+		m.status = "Order reversed"
+	} else {
+		m.status = "Order restored"
+	}
+}
+
+// cycleKeySort steps the list's sort through the key columns: partition
+// key ascending and descending, then the same for the sort key, then back
+// to the loaded order
+func (m *Model) cycleKeySort() tea.Cmd {
+	if len(m.tables) == 0 {
+		return nil
+	}
+	table := m.tables[m.currentTable]
+	cycle := []sortCriterion{{attr: table.PartitionKey}, {attr: table.PartitionKey, desc: true}}
+	if table.SortKey != "" {
+		cycle = append(cycle, sortCriterion{attr: table.SortKey}, sortCriterion{attr: table.SortKey, desc: true})
+	}
+
+	next := 0
+	if len(m.sortOrder) == 1 {
+		if i := slices.Index(cycle, m.sortOrder[0]); i >= 0 {
+			next = i + 1
+		}
+	}
+	if next == len(cycle) {
+		m.sortOrder = nil
+		m.restoreLoadedOrder()
+		m.status = "Unsorted"
+		return nil
+	}
+	return m.executeSort(formatSortOrder(cycle[next : next+1]))
+}
+
+// restoreLoadedOrder puts the items back in the order they were loaded in,
+// reversed if the list is. Items are matched by key, so ones replaced since,
+// like items fetched in full, keep their place; any not loaded go last.
+func (m *Model) restoreLoadedOrder() {
+	pos := make(map[string]int, len(m.loadedOrder))
+	for i, item := range m.loadedOrder {
+		pos[m.itemKey(item)] = i
+	}
+	position := func(item map[string]types.AttributeValue) int {
+		if i, ok := pos[m.itemKey(item)]; ok {
+			return i
+		}
+		return len(m.loadedOrder)
+	}
+	sort.SliceStable(m.items, func(i, j int) bool {
+		return position(m.items[i]) < position(m.items[j])
+	})
+	if m.reversed {
+		slices.Reverse(m.items)
+	}
+	m.cursor = 0
+	m.selected = make(map[int]bool)
+}
+
+// keySortArrow returns the arrow marking attr as the column the list is
+// sorted by, or "" if it isn't
+func (m *Model) keySortArrow(attr string) string {
+	if len(m.sortOrder) != 1 || m.sortOrder[0].attr != attr {
+		return ""
+	}
+	if m.sortOrder[0].desc {
+		return " ▼"
+	}
+	return " ▲"
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// newPasteArea returns the text area of the in-TUI insert mode
+func newPasteArea() textarea.Model {
+	ta := textarea.New()
+	ta.Placeholder = `{"id": "..."}`
+	ta.CharLimit = 0
+	ta.MaxHeight = 0
+	ta.ShowLineNumbers = true
+	return ta
+}
+
+// startPaste opens the in-TUI JSON editor for inserting an item without
+// $EDITOR. Text from a previous, unsubmitted insert is kept.
+func (m *Model) startPaste() tea.Cmd {
+	if len(m.tables) == 0 {
+		m.status = "No table selected"
+		return nil
+	}
+	if m.missingSchema() {
+		return nil
+	}
+	if strings.TrimSpace(m.paste.Value()) == "" {
+		m.paste.SetValue(m.newItemTemplate())
+	}
+	m.pasteErr = ""
+	m.mode = ModePaste
+	return m.paste.Focus()
+}
+
+func (m *Model) handlePasteMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.paste.Blur()
+		m.mode = ModeNormal
+		m.status = "Insert cancelled (I to continue)"
+		return m, nil
+
+	case "ctrl+s":
+		item, err := JSONToItem(m.paste.Value(), nil)
+		if err == nil {
+			err = m.tables[m.currentTable].checkKeys(item)
+		}
+		if err != nil {
+			// Keep the text so the mistake can be fixed in place
+			m.pasteErr = err.Error()
+			return m, nil
+		}
+		m.paste.Blur()
+		m.paste.Reset()
+		m.mode = ModeNormal
+		m.stagePending(item, nil)
+		return m, m.writeItem(item)
+	}
+
+	var cmd tea.Cmd
+	m.paste, cmd = m.paste.Update(msg)
+	return m, cmd
+}
+
+// renderPaste shows the insert text area with any parse error below it
+func (m *Model) renderPaste() string {
+	errLine := ""
+	if m.pasteErr != "" {
+		errLine = errorStyle.Render(truncate(m.pasteErr, max(m.width-2, 20)))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		headerStyle.Render("Insert item as JSON ("+m.tables[m.currentTable].Name+")"),
+		m.paste.View(),
+		errLine)
+}
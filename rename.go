@@ -0,0 +1,122 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pendingRename is a /rename waiting for confirmation
+type pendingRename struct {
+	from, to string
+	keys     []map[string]types.AttributeValue
+}
+
+// executeRename asks to confirm renaming an attribute across the selected
+// or loaded items
+func (m *Model) executeRename(args []string) tea.Cmd {
+	if m.missingSchema() || m.readOnly() {
+		return nil
+	}
+	if len(args) != 2 || args[0] == args[1] {
+		m.status = "Usage: /rename oldName newName"
+		return nil
+	}
+	if len(m.tables) == 0 {
+		m.status = "No table selected"
+		return nil
+	}
+	table := m.tables[m.currentTable]
+	for _, attr := range args {
+		if attr == table.PartitionKey || attr == table.SortKey {
+			m.status = fmt.Sprintf("Can't rename key attribute %s", attr)
+			return nil
+		}
+	}
+	items := m.targetItems()
+	if len(items) == 0 {
+		m.status = "No items loaded"
+		return nil
+	}
+
+	keys := make([]map[string]types.AttributeValue, 0, len(items))
+	seen := make(map[string]bool)
+	for _, item := range items {
+		// Index scans can list an item more than once
+		if id := m.itemKey(item); !seen[id] {
+			seen[id] = true
+			keys = append(keys, itemPrimaryKey(table, item))
+		}
+	}
+	m.rename = &pendingRename{from: args[0], to: args[1], keys: keys}
+	m.mode = ModeConfirmRename
+	return nil
+}
+
+// renameAttribute moves the attribute's value to its new name in each item
+// of the pending rename. Each item is updated in place on the server, so
+// attributes missing from an index projection aren't lost and changes made
+// since the items were loaded are kept.
+func (m *Model) renameAttribute() tea.Cmd {
+	r := m.rename
+	m.rename = nil
+	table := m.tables[m.currentTable]
+
+	return m.withSpinner(func() tea.Msg {
+		ctx := m.ctx
+		renamed, conflicts := 0, 0
+		for _, key := range r.keys {
+			err := m.ddb.RenameAttribute(ctx, table.Name, key, r.from, r.to)
+			var failed *types.ConditionalCheckFailedException
+			switch {
+			case errors.As(err, &failed):
+				// Items without the attribute are left alone; ones that
+				// already have the new name are reported
+				if _, exists := failed.Item[r.to]; exists {
+					conflicts++
+				}
+			case err != nil && renamed > 0:
+				return operationDoneMsg{err: fmt.Errorf("renamed %s in %d item(s), then: %w", r.from, renamed, err)}
+			case err != nil:
+				return operationDoneMsg{err: err}
+			default:
+				renamed++
+			}
+		}
+
+		status := fmt.Sprintf("Renamed %s to %s in %d item(s)", r.from, r.to, renamed)
+		if conflicts > 0 {
+			status += fmt.Sprintf(", skipped %d that already have %s", conflicts, r.to)
+		}
+		return operationDoneMsg{status: status}
+	})
+}
+
+func (m *Model) handleConfirmRenameMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		m.mode = ModeNormal
+		return m, m.renameAttribute()
+
+	case "n", "N", "esc":
+		m.mode = ModeNormal
+		m.rename = nil
+		m.status = "Rename cancelled"
+		return m, nil
+	}
+	return m, nil
+}
+
+// itemPrimaryKey returns the primary key attributes of item
+func itemPrimaryKey(table *TableInfo, item map[string]types.AttributeValue) map[string]types.AttributeValue {
+	key := map[string]types.AttributeValue{table.PartitionKey: item[table.PartitionKey]}
+	if table.SortKey != "" {
+		key[table.SortKey] = item[table.SortKey]
+	}
+	return key
+}
@@ -0,0 +1,195 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// seedDoneMsg reports a /seed: report lists the outcome of every file
+type seedDoneMsg struct {
+	status string
+	report string
+	wrote  bool
+	err    error
+}
+
+// seedFile is one fixture file of a /seed and the item read from it
+type seedFile struct {
+	path string
+	item map[string]types.AttributeValue
+	err  error
+}
+
+// executeSeed puts the items of a directory of .json files, one item per
+// file, into the current table. Every file is validated before anything is
+// written, so a bad fixture doesn't leave a partial seed. -r includes
+// subdirectories and -n only validates.
+func (m *Model) executeSeed(args []string) tea.Cmd {
+	if m.missingSchema() {
+		return nil
+	}
+	if len(m.tables) == 0 {
+		m.status = "No table selected"
+		return nil
+	}
+	recursive, dryRun := false, false
+	var dirs []string
+	for _, arg := range args {
+		switch arg {
+		case "-r":
+			recursive = true
+		case "-n":
+			dryRun = true
+		default:
+			dirs = append(dirs, arg)
+		}
+	}
+	if len(dirs) != 1 {
+		m.status = "Usage: /seed [-r] [-n] dir (-r subdirectories, -n validate only)"
+		return nil
+	}
+	if !dryRun && m.readOnly() {
+		return nil
+	}
+	dir := dirs[0]
+	table := m.tables[m.currentTable]
+
+	return m.withSpinner(func() tea.Msg {
+		files, err := readSeedFiles(dir, recursive, table)
+		if err != nil {
+			return seedDoneMsg{err: err}
+		}
+		if len(files) == 0 {
+			return seedDoneMsg{status: "No .json files in " + dir}
+		}
+		failed := 0
+		for _, f := range files {
+			if f.err != nil {
+				failed++
+			}
+		}
+		switch {
+		case failed > 0:
+			return seedDoneMsg{
+				status: fmt.Sprintf("Seed: %d of %d file(s) invalid, nothing written", failed, len(files)),
+				report: seedReport(files),
+			}
+		case dryRun:
+			return seedDoneMsg{
+				status: fmt.Sprintf("Seed dry run: all %d file(s) valid", len(files)),
+				report: seedReport(files),
+			}
+		}
+
+		written := writeSeedFiles(m.ctx, m.ddb, table.Name, files)
+		if errors.Is(m.ctx.Err(), context.Canceled) {
+			return seedDoneMsg{err: context.Canceled}
+		}
+		status := fmt.Sprintf("Seeded %d item(s) from %s", written, dir)
+		if written < len(files) {
+			status = fmt.Sprintf("Seeded %d of %d item(s) from %s, %d failed", written, len(files), dir, len(files)-written)
+		}
+		return seedDoneMsg{status: status, report: seedReport(files), wrote: written > 0}
+	})
+}
+
+// readSeedFiles reads and validates the .json files of dir, sorted by path.
+// Files whose item lacks the table's keys, or repeats the key of an earlier
+// file, are marked failed.
+func readSeedFiles(dir string, recursive bool, table *TableInfo) ([]seedFile, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	files := make([]seedFile, len(paths))
+	seen := make(map[string]string)
+	for i, path := range paths {
+		files[i].path = path
+		data, err := os.ReadFile(path)
+		if err != nil {
+			files[i].err = err
+			continue
+		}
+		item, err := JSONToItem(string(data), nil)
+		if err == nil {
+			err = table.checkKeys(item)
+		}
+		if err != nil {
+			files[i].err = err
+			continue
+		}
+		// A batch write rejects two puts of the same key. Keys are compared
+		// with their types, as N 1 and S "1" are different keys.
+		key := ItemToNativeJSON(itemPrimaryKey(table, item), "", nil)
+		if first, ok := seen[key]; ok {
+			files[i].err = fmt.Errorf("same key as %s", first)
+			continue
+		}
+		seen[key] = path
+		files[i].item = item
+	}
+	return files, nil
+}
+
+// writeSeedFiles batch-writes the items of files 25 at a time, marking the
+// files of a failed batch, and returns how many items were written
+func writeSeedFiles(ctx context.Context, ddb *DDB, tableName string, files []seedFile) int {
+	written := 0
+	for start := 0; start < len(files); start += 25 {
+		batch := files[start:min(start+25, len(files))]
+		items := make([]map[string]types.AttributeValue, len(batch))
+		for i, f := range batch {
+			items[i] = f.item
+		}
+		if err := ddb.BatchPutItems(ctx, tableName, items); err != nil {
+			for i := range batch {
+				batch[i].err = err
+			}
+			continue
+		}
+		written += len(batch)
+	}
+	return written
+}
+
+// seedReport lists each file of a /seed with its outcome
+func seedReport(files []seedFile) string {
+	var b strings.Builder
+	for _, f := range files {
+		if f.err != nil {
+			fmt.Fprintf(&b, "FAIL  %s: %v\n", f.path, f.err)
+		} else {
+			fmt.Fprintf(&b, "ok    %s\n", f.path)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
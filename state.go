@@ -0,0 +1,73 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// State is data dui remembers across runs, stored as JSON in the dui
+// config directory (e.g. ~/.config/dui/state.json)
+type State struct {
+	// Filters maps a saved filter name to its filter expression
+	Filters map[string]string `json:"filters,omitempty"`
+
+	// LastTables maps an endpoint to the table last selected on it
+	LastTables map[string]string `json:"last_tables,omitempty"`
+
+	// Favorites maps an endpoint to the tables pinned on it
+	Favorites map[string][]string `json:"favorites,omitempty"`
+}
+
+// configDir returns the directory where dui keeps its files
+func configDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot find config directory: %w", err)
+	}
+	return filepath.Join(dir, "dui"), nil
+}
+
+// loadState reads the state file. A missing file is not an error.
+func loadState() (*State, error) {
+	state := &State{}
+	dir, err := configDir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "state.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid state file: %w", err)
+	}
+	return state, nil
+}
+
+// save writes the state file, creating the config directory if needed
+func (s *State) save() error {
+	dir, err := configDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "state.json"), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	return nil
+}
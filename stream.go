@@ -0,0 +1,243 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// streamPollInterval is how often /stream reads new records
+const streamPollInterval = time.Second
+
+// maxStreamRecords caps the records /stream keeps, dropping the oldest
+const maxStreamRecords = 1000
+
+// streamTail is the state of a running /stream
+type streamTail struct {
+	table       string
+	trimHorizon bool
+	iterator    string // "" once the shard is closed
+	records     []streamRecord
+	cursor      int
+	gen         int
+	mode        Mode // the mode /stream was run from
+}
+
+// streamOpenedMsg carries the shard iterator a /stream starts reading at
+type streamOpenedMsg struct {
+	tail *streamTail
+	err  error
+}
+
+// streamRecordsMsg carries the records of one read of a /stream, gen
+// identifying it so reads of a stopped stream are dropped
+type streamRecordsMsg struct {
+	gen     int
+	records []streamRecord
+	next    string
+	err     error
+}
+
+// streamTickMsg triggers the next read of the /stream of gen
+type streamTickMsg struct {
+	gen int
+}
+
+// executeStream handles /stream [latest|trim_horizon]: it tails the most
+// recent shard of the current table's stream, from new records by default
+// or from the oldest the shard still has
+func (m *Model) executeStream(args []string) tea.Cmd {
+	if len(m.tables) == 0 {
+		m.status = "No table selected"
+		return nil
+	}
+	trimHorizon := false
+	switch {
+	case len(args) == 0 || len(args) == 1 && strings.EqualFold(args[0], "latest"):
+	case len(args) == 1 && strings.EqualFold(args[0], "trim_horizon"):
+		trimHorizon = true
+	default:
+		m.status = "Usage: /stream [latest|trim_horizon]"
+		return nil
+	}
+	table := m.tables[m.currentTable].Name
+	m.streamGen++
+	tail := &streamTail{table: table, trimHorizon: trimHorizon, gen: m.streamGen, mode: m.mode}
+
+	return m.withSpinner(func() tea.Msg {
+		it, err := m.ddb.OpenStream(m.ctx, table, trimHorizon)
+		tail.iterator = it
+		return streamOpenedMsg{tail: tail, err: err}
+	})
+}
+
+// handleStreamOpened shows the stream view and starts reading
+func (m *Model) handleStreamOpened(msg streamOpenedMsg) tea.Cmd {
+	if errors.Is(msg.err, context.Canceled) {
+		return nil
+	}
+	m.loading = false
+	if msg.err != nil {
+		m.setError(msg.err)
+		return nil
+	}
+	// Don't pull the user out of a view they opened while it was loading
+	if msg.tail.gen != m.streamGen || m.mode != msg.tail.mode {
+		return nil
+	}
+	m.stream = msg.tail
+	m.mode = ModeStream
+	from := "new records"
+	if msg.tail.trimHorizon {
+		from = "the oldest record"
+	}
+	m.status = fmt.Sprintf("Tailing the stream of %s from %s (Esc to stop)", msg.tail.table, from)
+	return m.readStream()
+}
+
+// readStream reads the records at the stream's iterator. It runs without
+// the spinner, since it runs every second.
+func (m *Model) readStream() tea.Cmd {
+	s := m.stream
+	if s == nil || s.iterator == "" {
+		return nil
+	}
+	gen, iterator := s.gen, s.iterator
+	return func() tea.Msg {
+		records, next, err := m.ddb.StreamRecords(m.ctx, iterator)
+		return streamRecordsMsg{gen: gen, records: records, next: next, err: err}
+	}
+}
+
+// handleStreamRecords adds newly read records, keeping the cursor on the
+// newest when it was there, and schedules the next read
+func (m *Model) handleStreamRecords(msg streamRecordsMsg) tea.Cmd {
+	s := m.stream
+	if s == nil || msg.gen != s.gen {
+		return nil
+	}
+	if errors.Is(msg.err, context.Canceled) {
+		m.stream = nil
+		return nil
+	}
+	if msg.err != nil {
+		m.stopStream()
+		m.setError(msg.err)
+		return nil
+	}
+	follow := s.cursor >= len(s.records)-1
+	s.records = append(s.records, msg.records...)
+	if drop := len(s.records) - maxStreamRecords; drop > 0 {
+		s.records = s.records[drop:]
+		s.cursor = max(s.cursor-drop, 0)
+	}
+	if follow {
+		s.cursor = max(len(s.records)-1, 0)
+	}
+	s.iterator = msg.next
+	if s.iterator == "" {
+		m.status = "The shard is closed; /stream again for the current one"
+		return nil
+	}
+	gen := s.gen
+	return tea.Tick(streamPollInterval, func(time.Time) tea.Msg {
+		return streamTickMsg{gen: gen}
+	})
+}
+
+// handleStreamTick reads the stream again unless it was stopped
+func (m *Model) handleStreamTick(msg streamTickMsg) tea.Cmd {
+	if m.stream == nil || msg.gen != m.stream.gen {
+		return nil
+	}
+	return m.readStream()
+}
+
+// stopStream stops tailing and returns to the list
+func (m *Model) stopStream() {
+	m.stream = nil
+	m.streamGen++
+	m.mode = ModeNormal
+	m.status = "Stopped tailing the stream"
+}
+
+func (m *Model) handleStreamMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := m.stream
+	last := len(s.records) - 1
+	switch msg.String() {
+	case "esc", "q":
+		m.stopStream()
+	case "j", "down":
+		s.cursor = min(s.cursor+1, max(last, 0))
+	case "k", "up":
+		s.cursor = max(s.cursor-1, 0)
+	case "g", "home":
+		s.cursor = 0
+	case "G", "end":
+		s.cursor = max(last, 0)
+	case "c":
+		s.records = nil
+		s.cursor = 0
+		m.status = "Cleared"
+	case "enter":
+		if last >= 0 {
+			m.showInfo(m.streamRecordText(s.records[s.cursor]))
+		}
+	}
+	return m, nil
+}
+
+// streamRecordText shows a record's old and new images
+func (m *Model) streamRecordText(r streamRecord) string {
+	image := func(title string, item map[string]types.AttributeValue) string {
+		if item == nil {
+			return title + ": (none)"
+		}
+		return title + ":\n" + m.display.prettyJSON(item)
+	}
+	return fmt.Sprintf("%s at %s\n\n%s\n\n%s", r.event, r.time.Format(time.DateTime),
+		image("Old image", r.oldImage), image("New image", r.newImage))
+}
+
+// renderStream lists the records read so far, newest last, with the
+// changed item's keys
+func (m *Model) renderStream(height int) string {
+	s := m.stream
+	visibleRows := height - 2
+	lines := []string{headerStyle.Render(fmt.Sprintf("Stream of %s: %d record(s)", m.cfg.tableLabel(s.table), len(s.records)))}
+	if len(s.records) == 0 {
+		lines = append(lines, statusStyle.Render("  Waiting for changes..."))
+	}
+
+	start := 0
+	if s.cursor >= visibleRows {
+		start = s.cursor - visibleRows + 1
+	}
+	table := m.tables[m.currentTable]
+	for i := start; i < len(s.records) && i < start+visibleRows; i++ {
+		r := s.records[i]
+		keys := GetKeyValue(r.keys, table.PartitionKey)
+		if table.SortKey != "" {
+			keys += "  " + GetKeyValue(r.keys, table.SortKey)
+		}
+		row := truncate(fmt.Sprintf(" %s  %-6s  %s", r.time.Format(time.TimeOnly), r.event, keys), max(m.width-4, 10))
+		if i == s.cursor {
+			lines = append(lines, cursorStyle.Render("▶ ")+selectedRowStyle.Render(row))
+		} else {
+			lines = append(lines, "  "+tableRowStyle.Render(row))
+		}
+	}
+
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
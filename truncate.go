@@ -0,0 +1,87 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pendingTruncate is a /truncate waiting for the table name to be typed
+type pendingTruncate struct {
+	table *TableInfo
+	keys  []map[string]types.AttributeValue
+}
+
+// truncateKeysMsg carries the keys of every item of a table to truncate
+type truncateKeysMsg struct {
+	table *TableInfo
+	keys  []map[string]types.AttributeValue
+	err   error
+}
+
+// executeTruncate reads the keys of every item in the current table, then
+// asks to confirm deleting them all
+func (m *Model) executeTruncate() tea.Cmd {
+	if m.readOnly() {
+		return nil
+	}
+	if len(m.tables) == 0 {
+		m.status = "No table selected"
+		return nil
+	}
+	if m.missingSchema() {
+		return nil
+	}
+	table := m.tables[m.currentTable]
+	return m.withSpinner(func() tea.Msg {
+		keys, err := m.ddb.ScanKeys(m.ctx, table, m.countPage)
+		return truncateKeysMsg{table: table, keys: keys, err: err}
+	})
+}
+
+// handleTruncateKeys asks for the table name before deleting every item
+func (m *Model) handleTruncateKeys(msg truncateKeysMsg) {
+	if len(msg.keys) == 0 {
+		m.status = "Table " + msg.table.Name + " is already empty"
+		return
+	}
+	m.truncate = &pendingTruncate{table: msg.table, keys: msg.keys}
+	m.input.SetValue("")
+	m.mode = ModeConfirmTruncate
+}
+
+func (m *Model) handleConfirmTruncateMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = ModeNormal
+		m.truncate = nil
+		m.input.SetValue("")
+		m.status = "Truncate cancelled"
+		return m, nil
+
+	case tea.KeyEnter:
+		t := m.truncate
+		typed := m.input.Value()
+		m.mode = ModeNormal
+		m.truncate = nil
+		m.input.SetValue("")
+		if typed != t.table.Name {
+			m.status = "Table name didn't match: truncate cancelled"
+			return m, nil
+		}
+		return m, m.withSpinner(func() tea.Msg {
+			if err := m.ddb.BatchDeleteItems(m.ctx, t.table.Name, t.keys); err != nil {
+				return operationDoneMsg{err: err}
+			}
+			return operationDoneMsg{status: fmt.Sprintf("Deleted all %d items from %s", len(t.keys), t.table.Name)}
+		})
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// toggleExpired shows only the items whose TTL has passed but that DynamoDB
+// hasn't deleted yet, or removes that filter if it's on. The TTL attribute
+// comes from the table's TTL settings.
+func (m *Model) toggleExpired() tea.Cmd {
+	if i := slices.IndexFunc(m.filters, func(f filterClause) bool { return f.op == filterExpired }); i >= 0 {
+		m.filters = slices.Delete(m.filters, i, i+1)
+		m.isFiltered = len(m.filters) > 0
+		m.cursor = 0
+		m.onHeader = false
+		m.selected = make(map[int]bool)
+		m.status = "Expired filter removed"
+		return nil
+	}
+	if len(m.tables) == 0 {
+		m.status = "No table selected"
+		return nil
+	}
+	table := m.tables[m.currentTable].Name
+	return m.withSpinner(func() tea.Msg {
+		attr, err := m.ddb.TimeToLiveAttribute(m.ctx, table)
+		return ttlLoadedMsg{table: table, attr: attr, err: err}
+	})
+}
+
+// applyExpiredFilter adds the expired items filter on the TTL attribute attr
+// of table, alongside any other filters
+func (m *Model) applyExpiredFilter(table, attr string) {
+	if len(m.tables) == 0 || m.tables[m.currentTable].Name != table {
+		return
+	}
+	if attr == "" {
+		m.status = "TTL isn't enabled on " + table
+		return
+	}
+	m.filters = append(m.filters, filterClause{attr: attr, op: filterExpired})
+	m.isFiltered = true
+	m.cursor = 0
+	m.onHeader = false
+	m.selected = make(map[int]bool)
+	m.status = "Showing items whose TTL (" + attr + ") has passed"
+}
+
+// isExpired reports whether av, a TTL attribute, is an epoch time in seconds
+// before now. Like DynamoDB, it ignores values that aren't numbers and times
+// more than five years past, which TTL never deletes.
+func isExpired(av types.AttributeValue, now time.Time) bool {
+	n, ok := av.(*types.AttributeValueMemberN)
+	if !ok {
+		return false
+	}
+	ttl, ok := new(big.Float).SetString(n.Value)
+	if !ok {
+		return false
+	}
+	oldest := big.NewFloat(float64(now.AddDate(-5, 0, 0).Unix()))
+	return ttl.Cmp(big.NewFloat(float64(now.Unix()))) < 0 && ttl.Cmp(oldest) >= 0
+}
@@ -5,8 +5,10 @@ package main
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -14,6 +16,7 @@ var (
 	primaryColor   = lipgloss.Color("39")  // blue
 	secondaryColor = lipgloss.Color("252") // light gray
 	errorColor     = lipgloss.Color("196") // red
+	warnColor      = lipgloss.Color("214") // orange
 	successColor   = lipgloss.Color("82")  // green
 	selectedColor  = lipgloss.Color("12")  // light blue
 	filterColor    = lipgloss.Color("5")   // magenta
@@ -30,6 +33,9 @@ var (
 	errorStyle = lipgloss.NewStyle().
 			Foreground(errorColor)
 
+	warnStyle = lipgloss.NewStyle().
+			Foreground(warnColor)
+
 	inputStyle = lipgloss.NewStyle().
 			Foreground(primaryColor)
 
@@ -90,8 +96,18 @@ func (m *Model) View() string {
 		b.WriteString(m.renderItemView(contentHeight))
 	case ModeErrorView:
 		b.WriteString(m.renderErrorView(contentHeight))
+	case ModeInfo:
+		b.WriteString(m.renderInfoView(contentHeight))
+	case ModeConfirmSave:
+		b.WriteString(m.renderDiffView(contentHeight))
 	case ModeConfirmDelete:
+		b.WriteString(m.renderDeleteConfirm(contentHeight))
+	case ModeConfirmRename, ModeConfirmTruncate:
 		b.WriteString(m.renderItems(contentHeight))
+	case ModePaste:
+		b.WriteString(m.renderPaste())
+	case ModeStream:
+		b.WriteString(m.renderStream(contentHeight))
 	case ModeFilter:
 		b.WriteString(m.renderItems(contentHeight))
 	default:
@@ -109,12 +125,7 @@ func (m *Model) renderHeader() string {
 	var tableName string
 	if len(m.tables) > 0 && m.currentTable < len(m.tables) {
 		table := m.tables[m.currentTable]
-		tableName = table.Name
-		if table.SortKey != "" {
-			tableName += fmt.Sprintf(" (PK: %s, SK: %s)", table.PartitionKey, table.SortKey)
-		} else {
-			tableName += fmt.Sprintf(" (PK: %s)", table.PartitionKey)
-		}
+		tableName = m.cfg.tableLabel(table.Name) + table.keysLabel()
 	} else {
 		tableName = "No table"
 	}
@@ -128,10 +139,36 @@ func (m *Model) renderHeader() string {
 			Render(fmt.Sprintf(" FILTERED: %d", len(m.filters)))
 	}
 
-	tableStr := headerStyle.Render(tableName) + filterIndicator
+	if m.watchInterval > 0 {
+		filterIndicator += lipgloss.NewStyle().
+			Bold(true).
+			Foreground(filterColor).
+			Render(fmt.Sprintf(" WATCH: %s", m.watchInterval))
+	}
+
+	breadcrumb := ""
+	if m.viewDesc != "" {
+		breadcrumb = statusStyle.Render(" › " + truncate(m.viewDesc, 40))
+	}
+	if !m.loadedAt.IsZero() {
+		breadcrumb += statusStyle.Render(" · loaded " + m.loadedAt.Format("15:04:05"))
+	}
+
+	tableStr := headerStyle.Render(tableName) + statusStyle.Render(" @ "+m.ddb.target()) + breadcrumb + filterIndicator
 
 	var statusStr string
-	if m.err != nil {
+	if m.loading {
+		text := "Loading..."
+		// Past the first page, show how far a long scan has come
+		if pages := m.loadedPages.Load(); pages > 1 {
+			text = fmt.Sprintf("Loading... %d pages, %d items so far", pages, m.loadedCount.Load())
+		} else if n := m.loadedCount.Load(); n > 0 {
+			text = fmt.Sprintf("Loading... %d items", n)
+		}
+		statusStr = m.spinner.View() + statusStyle.Render(" "+text)
+	} else if m.err != nil && m.errKind == errThrottled {
+		statusStr = warnStyle.Render(m.status)
+	} else if m.err != nil {
 		statusStr = errorStyle.Render(m.status)
 	} else {
 		statusStr = statusStyle.Render(m.status)
@@ -149,6 +186,12 @@ func (m *Model) renderItems(height int) string {
 		if m.isFiltered {
 			return strings.Repeat("\n", height-2) + statusStyle.Render("  No items match filter")
 		}
+		// A scan of the whole table found nothing: the table itself is empty
+		if m.emptyTable && !m.loading && len(m.tables) > 0 {
+			hint := headerStyle.Render("This table is empty") + "\n" +
+				statusStyle.Render("Press i to insert a new item")
+			return lipgloss.Place(m.width, height-1, lipgloss.Center, lipgloss.Center, hint)
+		}
 		return strings.Repeat("\n", height-2) + statusStyle.Render("  No items")
 	}
 
@@ -156,90 +199,329 @@ func (m *Model) renderItems(height int) string {
 		return strings.Repeat("\n", height-2) + statusStyle.Render("  No table selected")
 	}
 
+	var lines []string
+	layout := m.newListLayout(height - 1)
+	if layout.labels {
+		lines = append(lines, "  "+statusStyle.Render(m.columnLabels(layout)))
+	}
+	for _, entry := range m.listWindow(layout) {
+		lines = append(lines, entry.lines...)
+	}
+
+	// Pad remaining lines to fill content area
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	if len(lines) > height-1 {
+		lines = lines[:height-1]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// columnLabels renders the line labeling the key columns, with an arrow on
+// the one the list is sorted by
+func (m *Model) columnLabels(l listLayout) string {
+	table := l.table
+	// The arrow is one column but several bytes, so pad by display width
+	label := func(attr string, width int) string {
+		arrow := m.keySortArrow(attr)
+		s := truncate(attr, max(width-lipgloss.Width(arrow), 1)) + arrow
+		return s + strings.Repeat(" ", max(width-lipgloss.Width(s), 0))
+	}
+	pk := label(table.PartitionKey, l.pkWidth)
+	switch {
+	case l.skCols != nil:
+		return fmt.Sprintf(" %s │ %s │%s", pk, l.skCols.row(l.skCols.labels), m.keySortArrow(table.SortKey))
+	case table.SortKey != "":
+		return fmt.Sprintf(" %s │ %s │", pk, label(table.SortKey, l.skWidth))
+	}
+	return fmt.Sprintf(" %s │", pk)
+}
+
+// listLayout is how the rows of the list are laid out
+type listLayout struct {
+	table                       *TableInfo
+	pkWidth, skWidth, jsonWidth int
+	skCols                      *sortKeyColumns
+	labels                      bool     // label the key columns above the rows
+	expanded                    []string // lines of the expanded cursor row
+	visibleRows                 int      // lines for rows, below any label line
+}
+
+// newListLayout lays out the list of the current table in height lines
+func (m *Model) newListLayout(height int) listLayout {
 	table := m.tables[m.currentTable]
+	items := m.getFilteredItems()
+	l := listLayout{table: table, skCols: m.display.sortKeyColumns(table, items), visibleRows: height}
+	l.pkWidth, l.skWidth, l.jsonWidth = m.columnWidths(table)
+	// Label the sort key segments, or the key column the list is sorted by
+	l.labels = l.skCols != nil || m.keySortArrow(table.PartitionKey) != "" ||
+		table.SortKey != "" && m.keySortArrow(table.SortKey) != ""
+	if l.labels {
+		l.visibleRows--
+	}
+	l.expanded = m.expandedRowLines(items, l.jsonWidth, l.visibleRows)
+	return l
+}
 
-	// Calculate column widths
-	pkWidth := 20
-	skWidth := 20
-	jsonWidth := m.width - pkWidth - skWidth - 10
-	if table.SortKey == "" {
-		skWidth = 0
-		jsonWidth = m.width - pkWidth - 6
+// listEntry is a row shown in the list window with its rendered lines
+type listEntry struct {
+	row   groupRow
+	lines []string
+}
+
+// listWindow returns the rows the list shows, items or, when grouped, also
+// partition headers, scrolled so the cursor row fits. Rows can take several
+// lines, when expanded or wrapped.
+func (m *Model) listWindow(l listLayout) []listEntry {
+	items := m.getFilteredItems()
+	var rows []groupRow
+	pos := m.cursor
+	if m.grouped {
+		rows = m.groupRows()
+		pos = m.groupCursorRow(rows)
+	} else {
+		rows = make([]groupRow, len(items))
+		for i := range rows {
+			rows[i].idx = i
+		}
+	}
+	if len(rows) == 0 || l.visibleRows < 1 {
+		return nil
+	}
+	pos = max(min(pos, len(rows)-1), 0)
+	render := func(n int) []string {
+		if rows[n].header {
+			return []string{m.renderGroupHeader(rows[n], n == pos, m.width)}
+		}
+		return m.renderItemRow(items[rows[n].idx], rows[n].idx, l)
 	}
-	jsonWidth = max(20, jsonWidth)
 
-	var lines []string
+	// Show as many rows before the cursor row as fit with it
+	start, used := pos, len(render(pos))
+	for start > 0 {
+		height := len(render(start - 1))
+		if used+height > l.visibleRows {
+			break
+		}
+		start--
+		used += height
+	}
 
-	// Calculate visible range
-	visibleRows := height - 1
-	startIdx := 0
-	if m.cursor >= visibleRows {
-		startIdx = m.cursor - visibleRows + 1
+	var entries []listEntry
+	for n, used := start, 0; n < len(rows) && used < l.visibleRows; n++ {
+		lines := render(n)
+		entries = append(entries, listEntry{row: rows[n], lines: lines})
+		used += len(lines)
 	}
-	endIdx := startIdx + visibleRows
-	if endIdx > len(displayItems) {
-		endIdx = len(displayItems)
+	return entries
+}
+
+// renderItemRow renders the row of the item at index i, followed by the
+// continuation lines of the expanded row when the cursor is on it
+func (m *Model) renderItemRow(item map[string]types.AttributeValue, i int, l listLayout) []string {
+	table := l.table
+	onCursor := i == m.cursor && !m.onHeader
+	pk := truncate(GetKeyValue(item, table.PartitionKey), l.pkWidth)
+	sk := ""
+	if l.skCols != nil {
+		sk = l.skCols.row(l.skCols.split(GetKeyValue(item, table.SortKey)))
+	} else if table.SortKey != "" {
+		sk = truncate(GetKeyValue(item, table.SortKey), l.skWidth)
+	}
+	// Continuation lines of the JSON column, of an expanded or wrapped row
+	jsonStr := truncate(m.display.json(item), l.jsonWidth)
+	var more []string
+	switch {
+	case onCursor && len(l.expanded) > 0:
+		jsonStr, more = l.expanded[0], l.expanded[1:]
+	case m.wrapList && l.jsonWidth > 0:
+		wrapped := capLines(strings.Split(wrapText(m.display.json(item), l.jsonWidth), "\n"), l.visibleRows, l.jsonWidth)
+		jsonStr, more = wrapped[0], wrapped[1:]
+	}
+	if m.display.colorTypes {
+		base := lipgloss.NewStyle()
+		if onCursor {
+			base = base.Background(selectedRowStyle.GetBackground())
+		}
+		jsonStr = colorizeJSON(jsonStr, base)
 	}
 
-	for i := startIdx; i < endIdx; i++ {
-		item := displayItems[i]
+	count := ""
+	if m.display.attrCount {
+		count = fmt.Sprintf("%*d │ ", attrCountWidth-3, len(item))
+	}
 
-		pk := truncate(GetKeyValue(item, table.PartitionKey), pkWidth)
-		sk := ""
+	// Build row
+	var row string
+	switch {
+	case l.jsonWidth == 0 && table.SortKey != "":
+		row = fmt.Sprintf(" %-*s │ %s", l.pkWidth, pk, sk)
+	case l.jsonWidth == 0:
+		row = " " + pk
+	case table.SortKey != "":
+		row = fmt.Sprintf(" %-*s │ %-*s │ %s%s", l.pkWidth, pk, l.skWidth, sk, count, jsonStr)
+	default:
+		row = fmt.Sprintf(" %-*s │ %s%s", l.pkWidth, pk, count, jsonStr)
+	}
+	if l.jsonWidth == 0 && count != "" {
+		// Keys only: the count goes last, after the padded key columns
+		keys := fmt.Sprintf(" %-*s", l.pkWidth, pk)
 		if table.SortKey != "" {
-			sk = truncate(GetKeyValue(item, table.SortKey), skWidth)
+			keys = fmt.Sprintf(" %-*s │ %-*s", l.pkWidth, pk, l.skWidth, sk)
 		}
-		jsonStr := truncate(ItemToJSON(item), jsonWidth)
+		row = keys + " │ " + strings.TrimSuffix(count, " │ ")
+	}
 
-		// Build row
-		var row string
-		if table.SortKey != "" {
-			row = fmt.Sprintf(" %-*s │ %-*s │ %s", pkWidth, pk, skWidth, sk, jsonStr)
+	// Apply styling
+	if onCursor {
+		if m.selected[i] {
+			row = multiSelectStyle.Render("▶ ") + selectedRowStyle.Render(row)
 		} else {
-			row = fmt.Sprintf(" %-*s │ %s", pkWidth, pk, jsonStr)
+			row = cursorStyle.Render("▶ ") + selectedRowStyle.Render(row)
 		}
+	} else if m.selected[i] {
+		row = multiSelectStyle.Render("● ") + tableRowStyle.Render(row)
+	} else {
+		row = "  " + tableRowStyle.Render(row)
+	}
+	lines := []string{row}
 
-		// Apply styling
-		if i == m.cursor {
-			if m.selected[i] {
-				row = multiSelectStyle.Render("▶ ") + selectedRowStyle.Render(row)
-			} else {
-				row = cursorStyle.Render("▶ ") + selectedRowStyle.Render(row)
-			}
-		} else if m.selected[i] {
-			row = multiSelectStyle.Render("● ") + tableRowStyle.Render(row)
-		} else {
-			row = "  " + tableRowStyle.Render(row)
+	// Continuation lines, aligned to the JSON column
+	if len(more) > 0 {
+		indent := fmt.Sprintf(" %-*s │ %s", l.pkWidth, "", strings.Repeat(" ", lipgloss.Width(count)))
+		if table.SortKey != "" {
+			indent = fmt.Sprintf(" %-*s │ %-*s │ %s", l.pkWidth, "", l.skWidth, "", strings.Repeat(" ", lipgloss.Width(count)))
+		}
+		style := tableRowStyle
+		if onCursor {
+			style = selectedRowStyle
 		}
+		for _, line := range more {
+			lines = append(lines, "  "+style.Render(indent+line))
+		}
+	}
+	return lines
+}
 
-		lines = append(lines, row)
+// attrCountWidth is the width of the attribute count column with its
+// separator
+const attrCountWidth = 7
+
+// columnWidths returns the widths of the PK, SK, and JSON columns of the
+// list. In compact density the JSON column is dropped (jsonWidth is 0) and
+// the key columns share the row.
+func (m *Model) columnWidths(table *TableInfo) (pkWidth, skWidth, jsonWidth int) {
+	keyWidth := m.display.keyWidth
+	if m.display.density == densityComfortable {
+		keyWidth = max(keyWidth*3/2, m.width/4)
 	}
+	width := m.width
+	if m.display.attrCount {
+		width -= attrCountWidth
+	}
+	skCols := m.display.sortKeyColumns(table, m.getFilteredItems())
 
-	// Pad remaining lines to fill content area
-	for len(lines) < visibleRows {
-		lines = append(lines, "")
+	pkWidth = keyWidth
+	skWidth = keyWidth
+	if table.SortKey == "" {
+		skWidth = 0
+	} else if skCols != nil {
+		skWidth = skCols.width()
 	}
 
-	return strings.Join(lines, "\n")
+	if m.display.density == densityCompact {
+		switch {
+		case table.SortKey == "":
+			pkWidth = width - 4
+		case skCols != nil:
+			pkWidth = width - skWidth - 7
+		default:
+			pkWidth = (width - 7) / 2
+			skWidth = width - 7 - pkWidth
+		}
+		return max(pkWidth, 10), skWidth, 0
+	}
+
+	jsonWidth = width - pkWidth - skWidth - 10
+	if table.SortKey == "" {
+		jsonWidth = width - pkWidth - 6
+	}
+	jsonWidth = max(20, jsonWidth)
+	return pkWidth, skWidth, jsonWidth
+}
+
+// expandedRowLines returns the full JSON of the cursor row, compact or
+// indented, wrapped to jsonWidth when expanded row mode is on, or nil
+// otherwise. It's capped to visibleRows so the row always fits on screen.
+func (m *Model) expandedRowLines(items []map[string]types.AttributeValue, jsonWidth, visibleRows int) []string {
+	if !m.expandRow || m.onHeader || jsonWidth == 0 || visibleRows < 1 || m.cursor < 0 || m.cursor >= len(items) {
+		return nil
+	}
+	content := m.display.json(items[m.cursor])
+	if m.expandIndented {
+		content = m.display.prettyJSON(items[m.cursor])
+	}
+	return capLines(strings.Split(wrapText(content, jsonWidth), "\n"), visibleRows, jsonWidth)
+}
+
+// capLines cuts lines of at most width to the first n, marking the cut
+func capLines(lines []string, n, width int) []string {
+	if len(lines) > n {
+		lines = lines[:n]
+		lines[n-1] = truncate(lines[n-1]+"...", width)
+	}
+	return lines
+}
+
+// rowAt maps a line of the list (0 = first row below the header) to the
+// row rendered there, if any
+func (m *Model) rowAt(line, visibleRows int) (groupRow, bool) {
+	if len(m.tables) == 0 {
+		return groupRow{}, false
+	}
+	l := m.newListLayout(visibleRows)
+	// Skip the column label line
+	line -= visibleRows - l.visibleRows
+	if line < 0 || line >= l.visibleRows {
+		return groupRow{}, false
+	}
+	top := 0
+	for _, entry := range m.listWindow(l) {
+		if line < top+len(entry.lines) {
+			return entry.row, true
+		}
+		top += len(entry.lines)
+	}
+	return groupRow{}, false
 }
 
 func (m *Model) renderTableSelect(height int) string {
 	visibleRows := height - 1
 	var lines []string
-	lines = append(lines, headerStyle.Render("Select Table:"))
+	lines = append(lines, headerStyle.Render("Select Table:")+statusStyle.Render("  (Ctrl-F to pin/unpin)"))
 	lines = append(lines, "")
 
-	for i, table := range m.tables {
+	matches := m.tableMatches()
+	if len(matches) == 0 {
+		lines = append(lines, statusStyle.Render("  No tables match"))
+	}
+	for n, i := range matches {
+		table := m.tables[i]
+		// Favorites are pinned first; separate them from the rest
+		if n > 0 && m.favorites[m.tables[matches[n-1]].Name] && !m.favorites[table.Name] {
+			lines = append(lines, "")
+		}
 		prefix := "  "
 		if i == m.currentTable {
 			prefix = cursorStyle.Render("▶ ")
 		}
-		line := prefix + table.Name
-		if table.SortKey != "" {
-			line += statusStyle.Render(fmt.Sprintf(" (PK: %s, SK: %s)", table.PartitionKey, table.SortKey))
-		} else {
-			line += statusStyle.Render(fmt.Sprintf(" (PK: %s)", table.PartitionKey))
+		star := "  "
+		if m.favorites[table.Name] {
+			star = cursorStyle.Render("★ ")
 		}
+		line := prefix + star + m.cfg.tableLabel(table.Name) + statusStyle.Render(table.keysLabel())
 		lines = append(lines, line)
 	}
 
@@ -255,23 +537,7 @@ func (m *Model) renderItemView(height int) string {
 
 	if !m.showDataTypes {
 		// Normal view - just show values
-		content := overlayStyle.Render(m.viewContent)
-		contentLines := strings.Split(content, "\n")
-
-		// Start at top
-		result := contentLines
-
-		// Pad to fill screen
-		for len(result) < visibleRows {
-			result = append(result, "")
-		}
-
-		// Truncate to fit
-		if len(result) > visibleRows {
-			result = result[:visibleRows]
-		}
-
-		return strings.Join(result, "\n")
+		return m.renderOverlay(m.viewContent, height)
 	}
 
 	// Split-screen view: values on left, types on right
@@ -281,8 +547,9 @@ func (m *Model) renderItemView(height int) string {
 	}
 
 	// Get both value and type content
-	valueContent := ItemToPrettyJSON(item)
-	typeContent := ItemToDataTypes(item)
+	shown := m.display.viewItem(item)
+	valueContent := m.display.prettyJSON(shown)
+	typeContent := ItemToDataTypes(shown, m.display.indent)
 
 	// Calculate split width (50/50)
 	halfWidth := (m.width - 6) / 2
@@ -296,14 +563,29 @@ func (m *Model) renderItemView(height int) string {
 		BorderForeground(primaryColor).
 		Padding(1).
 		Width(halfWidth).
-		Height(visibleRows - 2)
+		Height(visibleRows - 2).
+		MaxHeight(visibleRows)
 
 	rightStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(successColor).
 		Padding(1).
 		Width(halfWidth).
-		Height(visibleRows - 2)
+		Height(visibleRows - 2).
+		MaxHeight(visibleRows)
+
+	// Explain the type codes at the bottom of the types panel if they fit,
+	// within the panel's padding
+	innerRows := visibleRows - 4
+	if legend := typeLegend(typeContent, halfWidth-2); len(legend) > 0 {
+		lines := strings.Split(typeContent, "\n")
+		if len(lines)+1+len(legend) <= innerRows {
+			for len(lines) < innerRows-len(legend) {
+				lines = append(lines, "")
+			}
+			typeContent = strings.Join(lines, "\n") + "\n" + statusStyle.Render(strings.Join(legend, "\n"))
+		}
+	}
 
 	leftPanel := leftStyle.Render(valueContent)
 	rightPanel := rightStyle.Render(typeContent)
@@ -312,6 +594,40 @@ func (m *Model) renderItemView(height int) string {
 	return lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, rightPanel)
 }
 
+// typeNames spells out DynamoDB's type codes, in the order the legend
+// lists them
+var typeNames = []struct{ code, name string }{
+	{"S", "String"}, {"N", "Number"}, {"B", "Binary"}, {"BOOL", "Boolean"},
+	{"NULL", "Null"}, {"M", "Map"}, {"L", "List"}, {"SS", "String Set"},
+	{"NS", "Number Set"}, {"BS", "Binary Set"},
+}
+
+// typeLegend explains the type codes used in typeContent, packed into
+// lines of at most width
+func typeLegend(typeContent string, width int) []string {
+	var lines []string
+	line := ""
+	for _, t := range typeNames {
+		if !strings.Contains(typeContent, `"`+t.code+`"`) {
+			continue
+		}
+		entry := t.code + "=" + t.name
+		switch {
+		case line == "":
+			line = entry
+		case len(line)+2+len(entry) <= width:
+			line += ", " + entry
+		default:
+			lines = append(lines, line)
+			line = entry
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
 func (m *Model) renderErrorView(height int) string {
 	visibleRows := height - 1
 	// Wrap text to fit window (leave room for border and padding)
@@ -344,35 +660,345 @@ func (m *Model) renderErrorView(height int) string {
 	return strings.Join(result, "\n")
 }
 
+func (m *Model) renderInfoView(height int) string {
+	lines := strings.Split(m.viewContent, "\n")
+	return m.renderOverlay(strings.Join(lines[min(m.infoScroll, len(lines)-1):], "\n"), height)
+}
+
+// deletePageRows is the number of keys the delete confirmation shows at
+// once, below its title and blank line and above the line counting the rest
+func (m *Model) deletePageRows() int {
+	return max(m.pageRows()-3, 1)
+}
+
+// renderDeleteConfirm lists the keys of the items dd is about to delete, a
+// page at a time from deleteScroll
+func (m *Model) renderDeleteConfirm(height int) string {
+	visibleRows := height - 1
+	indexes := m.deleteIndexes()
+	if len(m.tables) == 0 || len(indexes) == 0 {
+		return strings.Repeat("\n", visibleRows-1)
+	}
+	table := m.tables[m.currentTable]
+	items := m.getFilteredItems()
+
+	lines := []string{headerStyle.Render(fmt.Sprintf("Delete from %s:", m.cfg.tableLabel(table.Name))), ""}
+	start := min(m.deleteScroll, len(indexes)-1)
+	end := min(start+m.deletePageRows(), len(indexes))
+	pkWidth := 0
+	for _, idx := range indexes[start:end] {
+		pkWidth = max(pkWidth, len(truncate(GetKeyValue(items[idx], table.PartitionKey), m.display.keyWidth*2)))
+	}
+	for _, idx := range indexes[start:end] {
+		line := fmt.Sprintf("  %-*s", pkWidth, truncate(GetKeyValue(items[idx], table.PartitionKey), m.display.keyWidth*2))
+		if table.SortKey != "" {
+			line += "  " + GetKeyValue(items[idx], table.SortKey)
+		}
+		lines = append(lines, truncate(line, max(m.width-2, 10)))
+	}
+	if start > 0 || end < len(indexes) {
+		more := ""
+		if end < len(indexes) {
+			more = fmt.Sprintf("...and %d more  ", len(indexes)-end)
+		}
+		keys := "j/k"
+		if m.deleteConfirmText() != "" {
+			// Letters go to the typed confirmation
+			keys = "↑/↓"
+		}
+		lines = append(lines, statusStyle.Render(fmt.Sprintf("  %s(%d-%d of %d, %s to scroll)", more, start+1, end, len(indexes), keys)))
+	}
+
+	for len(lines) < visibleRows {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines[:visibleRows], "\n")
+}
+
+// renderOverlay boxes content, wrapped to the current terminal width so it
+// reflows on resize, and pads or cuts it to fill height
+func (m *Model) renderOverlay(content string, height int) string {
+	visibleRows := height - 1
+	// Leave room for the border and padding
+	wrapped := wrapText(content, max(m.width-6, 20))
+	result := strings.Split(overlayStyle.Render(wrapped), "\n")
+
+	// Pad to fill screen
+	for len(result) < visibleRows {
+		result = append(result, "")
+	}
+
+	// Truncate to fit
+	if len(result) > visibleRows {
+		result = result[:visibleRows]
+	}
+
+	return strings.Join(result, "\n")
+}
+
+// renderDiffView shows the pending changes of an edit, colored by kind
+func (m *Model) renderDiffView(height int) string {
+	visibleRows := height - 1
+	addedStyle := lipgloss.NewStyle().Foreground(successColor)
+	removedStyle := lipgloss.NewStyle().Foreground(errorColor)
+	changedStyle := lipgloss.NewStyle().Foreground(primaryColor)
+	warnStyle := lipgloss.NewStyle().Foreground(warnColor).Bold(true)
+
+	maxWidth := max(m.width-6, 20)
+	var lines []string
+	for _, line := range strings.Split(m.viewContent, "\n") {
+		line = truncate(line, maxWidth)
+		switch {
+		case strings.HasPrefix(line, "+"):
+			line = addedStyle.Render(line)
+		case strings.HasPrefix(line, "-"):
+			line = removedStyle.Render(line)
+		case strings.HasPrefix(line, "~"):
+			line = changedStyle.Render(line)
+		case strings.HasPrefix(line, "!"):
+			line = warnStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	content := overlayStyle.Render(strings.Join(lines, "\n"))
+	result := strings.Split(content, "\n")
+	for len(result) < visibleRows {
+		result = append(result, "")
+	}
+	if len(result) > visibleRows {
+		result = result[:visibleRows]
+	}
+	return strings.Join(result, "\n")
+}
+
+// tableInfoText describes a table's key schema and indexes for /info
+func tableInfoText(table *TableInfo) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Table:         %s\n", table.Name)
+	if table.DescribeErr != nil {
+		fmt.Fprintf(&b, "Schema:        unknown (%v)\n", table.DescribeErr)
+	}
+	fmt.Fprintf(&b, "Partition key: %s\n", keyLabel(table.PartitionKey, table.PartitionKeyType))
+	if table.SortKey != "" {
+		fmt.Fprintf(&b, "Sort key:      %s\n", keyLabel(table.SortKey, table.SortKeyType))
+	}
+
+	writeIndexes := func(title string, indexes []IndexInfo) {
+		if len(indexes) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n%s:\n", title)
+		for _, idx := range indexes {
+			keys := "PK: " + idx.PartitionKey
+			if idx.SortKey != "" {
+				keys += ", SK: " + idx.SortKey
+			}
+			fmt.Fprintf(&b, "  %s (%s)\n", idx.Name, keys)
+			fmt.Fprintf(&b, "    Projection: %s\n", projectionText(idx))
+		}
+	}
+	writeIndexes("Global secondary indexes", table.GlobalIndexes)
+	writeIndexes("Local secondary indexes", table.LocalIndexes)
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// itemSizeText breaks down an item's size by attribute, largest first
+func itemSizeText(item map[string]types.AttributeValue) string {
+	names := make([]string, 0, len(item))
+	width := len("Attribute")
+	for name := range item {
+		names = append(names, name)
+		width = max(width, len(name))
+	}
+	sort.Slice(names, func(i, j int) bool {
+		si, sj := AttributeSize(names[i], item[names[i]]), AttributeSize(names[j], item[names[j]])
+		if si != sj {
+			return si > sj
+		}
+		return names[i] < names[j]
+	})
+
+	total := ItemSize(item)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-*s  %10s  %6s\n", width, "Attribute", "Bytes", "Share")
+	for _, name := range names {
+		size := AttributeSize(name, item[name])
+		share := 0.0
+		if total > 0 {
+			share = float64(size) * 100 / float64(total)
+		}
+		fmt.Fprintf(&b, "%-*s  %10d  %5.1f%%\n", width, name, size, share)
+	}
+	fmt.Fprintf(&b, "\n%-*s  %10d  %5.1f%% of the 400 KB item limit", width, "Total", total,
+		float64(total)*100/float64(ItemSizeLimit))
+	return b.String()
+}
+
+// partitionText is a histogram of the top n partition key values of items
+// by item count
+func partitionText(items []map[string]types.AttributeValue, pkAttr string, n int) string {
+	counts := make(map[string]int)
+	for _, item := range items {
+		counts[GetKeyValue(item, pkAttr)]++
+	}
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d items in %d partitions", len(items), len(keys))
+	if len(keys) > n {
+		fmt.Fprintf(&b, " (top %d)", n)
+		keys = keys[:n]
+	}
+	b.WriteString("\n\n")
+
+	width := 0
+	for _, k := range keys {
+		width = max(width, len(truncate(k, 30)))
+	}
+	const barWidth = 30
+	for _, k := range keys {
+		count := counts[k]
+		bar := max(count*barWidth/counts[keys[0]], 1)
+		fmt.Fprintf(&b, "%-*s  %6d  %5.1f%%  %s\n", width, truncate(k, 30), count,
+			float64(count)*100/float64(len(items)), strings.Repeat("█", bar))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// projectionText describes which attributes an index projects
+func projectionText(idx IndexInfo) string {
+	switch idx.Projection {
+	case "":
+		return "unknown"
+	case "INCLUDE":
+		return "INCLUDE " + strings.Join(idx.NonKeyAttributes, ", ")
+	default:
+		return idx.Projection
+	}
+}
+
 func (m *Model) renderHelp(height int) string {
 	help := `
 Keyboard Shortcuts:
   ↑/k, ↓/j    Move cursor up/down
   gg          Go to first item
   G           Go to last item
+  Ctrl-D/U    Move down/up half a page
+  PgDn/PgUp   Move down/up a full page
+  l, →        Query the partition of the current item
+  h, ←        Back to the list before the partition query
   Enter       View item details
   Space       Toggle multi-select
   e           Edit current item in $EDITOR
+  E           Edit current item as DynamoDB JSON (explicit types)
   dd          Delete selected/current item(s)
   i, a        Insert new item (PutItem)
-  f           Filter items (CSV: attr=value, attr2=value2)
+  I           Insert new item by typing or pasting JSON here, without $EDITOR
+  f           Filter items (CSV: attr=value, attr2?, !attr3?)
   s           Scan/refresh current table
-  t           Select table
+  c           Copy last scan/query as an AWS CLI command
+  t           Select table (Ctrl-F there pins a favorite to the top)
+  o           Cycle the current row: full JSON, indented JSON, collapsed
+  w           Toggle wrapping every row's JSON instead of truncating it
+  r           Reverse the order of the loaded items
+  O           Sort by key: PK asc, PK desc, SK asc, SK desc, unsorted
+  P           Group the list by partition key; z (or Enter on a header)
+              folds a group, Z folds all, Space on a header selects it
+  ,           Toggle thousands separators for numbers (display only)
+  T           Toggle epoch timestamps as dates (*_at, ttl, timestamp)
+  @           Edit the last command to run it again
+  Backspace   Step back through the items viewed and partitions drilled into
+  X           Show only items whose TTL has passed but aren't deleted yet
+  C           Toggle coloring values by type in the list
+  #           Toggle a column with each item's attribute count
+  D           Cycle list density (normal, comfortable, compact)
   x           (In item view) Toggle data type display
+  S           (In item view) Show attribute sizes
+  h           (In item view) Hide/show empty and null attributes
+  :focus path (In item view) Show only a subtree, e.g. :focus meta.address;
+              Backspace shows the whole item again
   ?           Show this help
   Esc         Cancel/close
+  Mouse       Click row to move, click left edge to select, wheel to scroll
 
 Commands:
   /scan [index]                    Scan table or index
-  /query [index] pk=value          Query by partition key
+  /scan keys                       Scan only the keys; items load in full when opened
+  /query [index] pk=v [sk<op>v]    Query by partition key and optional sort
+                                   key condition (op: =, <, <=, >, >=)
+  /sk <op> value                   Narrow the last query's partition by sort key
+                                   (op: = < <= > >= begins_with between)
+  /head [n], /tail [n]             Re-run the query (or scan) and show only the
+                                   first or last n items (default 10)
+  /count [index] [pk=v [sk<op>v]]  Count items without loading them
+         [filter:attr<op>v ...]    (with server-side filter conditions; attr can be
+                                   a path like items[0].price)
   /get pk [sk]                     Get single item by primary key
+  /get k1 k2 ...                   Get several items (pk:sk with a sort key;
+                                   escape a colon in the pk as \:)
+  /get "user 1" "2024 Q1"          Quote arguments that contain spaces
+  /txget k1 k2 ...                 Get items by key in one consistent transaction
   /put                             Put new item (opens editor)
+  /put k=v[:TYPE] ...              Put an item built from the pairs (e.g. age=30:N)
   /update pk [sk]                  Update item (opens editor)
   /delete pk [sk]                  Delete item
   /rm pk [sk]                      Delete item (alias)
+  /filter save|load name           Save current filters or apply saved ones
+  /filter list                     List saved filters
+  /watch [interval]                Re-run the scan or query every interval (Esc stops)
+  :set attr=value                  Set one attribute of the current item
+  /grep [-i] [-e] term             Keep items with term in any value (-i case, -e regex)
+  /sort attr [asc|desc], ...       Sort loaded items by one or more attributes
+  /sort off                        Stop sorting reloaded items
+  /truncate                        Delete every item in the table (asks for its name)
+  /rename old new                  Rename an attribute in selected or loaded items
+  /export file [format]            Write selected or loaded items to a file
+                                   (jsonl, json, dynamodb-jsonl, dynamodb-json)
+  /csv file                        Write selected or loaded items as CSV
+  /stream [latest|trim_horizon]    Tail changes from the table's stream (newest shard)
+  /seed [-r] [-n] dir              Put one item per .json file in dir, checking
+                                   every file first (-r subdirectories, -n only check)
   /?                               Show this help
   /err                             Show last error
+  :version                         Show dui version
+  :table name, /use name           Switch to a table by (fuzzy) name
+  /endpoint url                    Switch to another endpoint (docker:// for the
+                                   DynamoDB Local container)
+  /region name                     Switch region
+  /info                            Show table keys, indexes, and projections
+  /keys pk [sk]                    Set the keys of a table that couldn't be described
+  /gostruct                        Copy a Go struct and literal of the current item
+  /describe [table]                Show the full DescribeTable output as JSON
+  /partitions [n]                  Histogram of the top n partition keys (default 20)
   /q, :q, :quit                    Quit
+  :w                               Write an edited item whose save was cancelled
+  :wq, :x                          Write it (if any) and quit
+
+Filters:
+  attr=value                       Attribute contains value (case-sensitive)
+  attr=value/i                     Attribute contains value, ignoring case
+  attr~regex                       Attribute matches regex
+  name begins_with Jo              String form of the attribute starts with Jo
+  tags contains admin              Set or list has the element admin, or string
+                                   has the substring (both take /i too)
+  meta.region=us, items[0].sku?    Attributes can be paths into maps and lists
+  *=value, *~regex                 Any attribute value matches (also nested)
+  attr?                            Attribute exists
+  !attr?                           Attribute is missing
+  expired(attr)                    TTL attribute attr is in the past (X toggles it
+                                   for the table's TTL attribute)
+  Set "ignore_case": true in ~/.config/dui/config.json to ignore case by default.
 
 Type Hints:
   When editing items, use <TYPE> suffix to specify DynamoDB types:
@@ -383,9 +1009,11 @@ Type Hints:
     "config<M>": {...}          → Map
     "active<BOOL>": true        → Boolean
     "empty<NULL>": null         → Null
+    "keys<BS>": ["aGk=", "AQI="] → Binary Set (base64 elements, as is B)
 
   Supported types: S, N, BOOL, NULL, L, M, SS, NS, B, BS
   Type hints are removed from attribute names after conversion.
+  Comments (// and /* */) and trailing commas are allowed.
 
 Press Esc or ? to close
 `
@@ -395,24 +1023,49 @@ Press Esc or ? to close
 func (m *Model) renderInput() string {
 	switch m.mode {
 	case ModeConfirmDelete:
-		count := len(m.selected)
-		if count == 0 {
-			count = 1
+		if text := m.deleteConfirmText(); text != "" {
+			return errorStyle.Render(fmt.Sprintf("Delete %s items? Type %s (or yes) and Enter to confirm, Esc to cancel: ", text, text)) + m.input.View()
 		}
-		return errorStyle.Render(fmt.Sprintf("Delete %d item(s)? (y/n) ", count))
+		return errorStyle.Render(fmt.Sprintf("Delete %d item(s)? (y/n) ", len(m.deleteIndexes())))
+
+	case ModeConfirmTruncate:
+		return errorStyle.Render(fmt.Sprintf("Delete ALL %d items from %s? This can't be undone. Type the table name to confirm: ",
+			len(m.truncate.keys), m.truncate.table.Name)) + m.input.View()
+
+	case ModeConfirmRename:
+		return errorStyle.Render(fmt.Sprintf("Rename %s to %s in %d item(s)? (y/n) ",
+			m.rename.from, m.rename.to, len(m.rename.keys)))
 
 	case ModeTableSelect:
-		return statusStyle.Render("Press Enter to select, Esc to cancel")
+		if m.tableQuery != "" {
+			return "Filter: " + m.tableQuery + statusStyle.Render("  (↑/↓ move, Enter select, Esc clear)")
+		}
+		return statusStyle.Render("Type to filter, ↑/↓ to move, Enter to select, Esc to cancel")
 
 	case ModeItemView:
 		if m.showDataTypes {
-			return statusStyle.Render("Press x to hide types, Enter/q/Esc to close")
+			return statusStyle.Render("Press x to hide types, S for sizes, Enter/q/Esc to close")
 		}
-		return statusStyle.Render("Press x to show types, Enter/q/Esc to close")
+		return statusStyle.Render("Press x to show types, S for sizes, Enter/q/Esc to close")
 
 	case ModeErrorView:
 		return errorStyle.Render("Press Enter, q, or Esc to close")
 
+	case ModePaste:
+		return statusStyle.Render("Ctrl-S to insert, Esc to leave (the text is kept)")
+
+	case ModeInfo:
+		return statusStyle.Render("j/k to scroll, y to copy, Enter, q, or Esc to close")
+
+	case ModeStream:
+		return statusStyle.Render("j/k to move, Enter for old and new images, c to clear, q or Esc to stop")
+
+	case ModeConfirmSave:
+		if m.forceWrite {
+			return errorStyle.Render("Overwrite anyway? (y/N) ")
+		}
+		return errorStyle.Render("Write item with these changes? (y/N) ")
+
 	case ModeHelp:
 		return statusStyle.Render("Press ? or Esc to close")
 
@@ -0,0 +1,134 @@
+// Copyright 2026 mlrd.tech, Inc.
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultWatchInterval is used by /watch without an interval
+const defaultWatchInterval = 5 * time.Second
+
+// minWatchInterval keeps watch mode from hammering the endpoint
+const minWatchInterval = time.Second
+
+// watchTickMsg triggers a watch refresh. gen identifies the watch that
+// scheduled it, so ticks of a stopped watch are dropped.
+type watchTickMsg struct {
+	gen int
+}
+
+// startWatch re-runs the last scan, query, or count every interval
+func (m *Model) startWatch(interval time.Duration) tea.Cmd {
+	m.watchInterval = max(interval, minWatchInterval)
+	m.watchGen++
+	m.status = fmt.Sprintf("Watching every %s (Esc to stop)", m.watchInterval)
+	return m.watchTick()
+}
+
+// stopWatch turns watch mode off
+func (m *Model) stopWatch() {
+	if m.watchInterval == 0 {
+		return
+	}
+	m.watchInterval = 0
+	m.watchGen++
+	m.status = "Stopped watching"
+}
+
+// watchTick schedules the next watch refresh
+func (m *Model) watchTick() tea.Cmd {
+	gen := m.watchGen
+	return tea.Tick(m.watchInterval, func(time.Time) tea.Msg {
+		return watchTickMsg{gen: gen}
+	})
+}
+
+// executeWatch handles /watch [interval], toggling watch mode
+func (m *Model) executeWatch(args []string) tea.Cmd {
+	if len(args) == 0 {
+		if m.watchInterval > 0 {
+			m.stopWatch()
+			return nil
+		}
+		return m.startWatch(defaultWatchInterval)
+	}
+	interval, err := time.ParseDuration(args[0])
+	if err != nil || interval <= 0 {
+		m.status = "Usage: /watch [interval], e.g. /watch 5s"
+		return nil
+	}
+	if m.watchInterval == max(interval, minWatchInterval) {
+		m.stopWatch()
+		return nil
+	}
+	return m.startWatch(interval)
+}
+
+// handleWatchTick refreshes the list unless a load is still in flight, so
+// refreshes never stack up behind a slow one
+func (m *Model) handleWatchTick(msg watchTickMsg) tea.Cmd {
+	if msg.gen != m.watchGen || m.watchInterval == 0 {
+		return nil
+	}
+	if m.loading || m.mode != ModeNormal || len(m.tables) == 0 {
+		return m.watchTick()
+	}
+	return tea.Batch(m.refresh(), m.watchTick())
+}
+
+// refresh re-runs the last scan, query, or count
+func (m *Model) refresh() tea.Cmd {
+	op := m.lastOp
+	if op == nil {
+		op = &operation{kind: "scan", table: m.tables[m.currentTable].Name, maxItems: m.cfg.maxItems()}
+	}
+	if op.count {
+		return m.withSpinner(func() tea.Msg {
+			count, scanned, err := m.ddb.Count(m.ctx, op, m.countPage)
+			return countLoadedMsg{count: count, scanned: scanned, filtered: op.filter != "", err: err}
+		})
+	}
+	return m.withSpinner(func() tea.Msg {
+		items, err := m.ddb.Run(m.ctx, op, m.countPage)
+		return itemsLoadedMsg{items: items, err: err, refresh: true, capped: op.maxItems > 0 && len(items) >= op.maxItems,
+			emptyTable: err == nil && len(items) == 0 && op.readsWholeTable()}
+	})
+}
+
+// itemKey identifies an item by its primary key values
+func (m *Model) itemKey(item map[string]types.AttributeValue) string {
+	table := m.tables[m.currentTable]
+	key := GetKeyValue(item, table.PartitionKey)
+	if table.SortKey != "" {
+		key += "\x00" + GetKeyValue(item, table.SortKey)
+	}
+	return key
+}
+
+// applyRefresh replaces the items with refreshed ones, keeping the cursor on
+// the same item when it's still there
+func (m *Model) applyRefresh(items []map[string]types.AttributeValue) {
+	current := m.getCurrentItem()
+	m.items = items
+	m.orderItems()
+	m.selected = make(map[int]bool)
+	m.status = fmt.Sprintf("Refreshed %d items at %s", len(items), time.Now().Format("15:04:05"))
+	if current == nil {
+		return
+	}
+	key := m.itemKey(current)
+	filtered := m.getFilteredItems()
+	for i, item := range filtered {
+		if m.itemKey(item) == key {
+			m.cursor = i
+			return
+		}
+	}
+	m.cursor = min(m.cursor, max(len(filtered)-1, 0))
+}